@@ -0,0 +1,118 @@
+package jsonschematogo
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/willabides/jsonschematogo/internal/codegen"
+)
+
+// Hook customizes the Go code that Render generates, for example to add methods to generated types
+// or tags to their fields. Hooks are called in the order of Config.Hooks for every type and struct
+// field. Embed BaseHook to implement only one of the methods.
+//
+// The x-go-tags extension is implemented by a built-in hook that runs before Config.Hooks.
+type Hook interface {
+	// Type is called for every generated type after its fields.
+	Type(ctx *TypeHookContext) error
+	// Field is called for every field of a generated struct.
+	Field(ctx *FieldHookContext) error
+}
+
+// BaseHook is a Hook that does nothing.
+type BaseHook struct{}
+
+func (BaseHook) Type(*TypeHookContext) error { return nil }
+
+func (BaseHook) Field(*FieldHookContext) error { return nil }
+
+// TypeHookContext is the generated type a Hook is called for.
+type TypeHookContext struct {
+	// Name is the type name.
+	Name string
+
+	// Schema is the schema the type is generated from.
+	Schema *Schema
+
+	// Extensions are the schema's keywords that start with "x-", including ones that
+	// jsonschematogo doesn't know.
+	Extensions map[string]any
+
+	// Statement is the type declaration. Hooks may modify it.
+	Statement *jen.Statement
+
+	// After is code that is added after the declaration, such as methods.
+	After []jen.Code
+}
+
+// FieldHookContext is the struct field a Hook is called for.
+type FieldHookContext struct {
+	// TypeName is the name of the struct.
+	TypeName string
+
+	// Name is the field name.
+	Name string
+
+	// JSONName is the property name.
+	JSONName string
+
+	// Required reports whether the property is required.
+	Required bool
+
+	// Schema is the property's schema.
+	Schema *Schema
+
+	// Extensions are the property schema's keywords that start with "x-".
+	Extensions map[string]any
+
+	// Statement is the field's name and type without the tags. Hooks may modify it.
+	Statement *jen.Statement
+
+	// Tags are the field's struct tags keyed by name. They start with the json tag.
+	Tags map[string]string
+}
+
+// codegenHook calls a Hook from the code generator.
+type codegenHook struct {
+	hook     Hook
+	inMemory func(document string) bool
+}
+
+func (h *codegenHook) Type(ctx *codegen.TypeHookContext) error {
+	hookCtx := &TypeHookContext{
+		Name:      ctx.Decl.Name,
+		Statement: ctx.Statement,
+		After:     ctx.After,
+	}
+	if ctx.Schema != nil {
+		hookCtx.Schema = newSchema(ctx.Schema, h.inMemory)
+		hookCtx.Extensions = ctx.Schema.ExtensionKeywords()
+	}
+	err := h.hook.Type(hookCtx)
+	if err != nil {
+		return err
+	}
+	ctx.Statement = hookCtx.Statement
+	ctx.After = hookCtx.After
+	return nil
+}
+
+func (h *codegenHook) Field(ctx *codegen.FieldHookContext) error {
+	hookCtx := &FieldHookContext{
+		TypeName:  ctx.Decl.Name,
+		Name:      ctx.Field.Name,
+		JSONName:  ctx.Field.JSONName,
+		Required:  ctx.Field.Required,
+		Statement: ctx.Statement,
+		Tags:      ctx.Tags,
+	}
+	if ctx.Schema != nil {
+		hookCtx.Schema = newSchema(ctx.Schema, h.inMemory)
+		hookCtx.Extensions = ctx.Schema.ExtensionKeywords()
+	}
+	err := h.hook.Field(hookCtx)
+	if err != nil {
+		return err
+	}
+	ctx.Statement = hookCtx.Statement
+	ctx.Tags = hookCtx.Tags
+	return nil
+}
@@ -0,0 +1,108 @@
+package codegen
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// wantBuilders reports whether a constructor and setters are generated for the struct generated
+// from sch. It declares the Ptr helper in the struct's package and returns an error when a setter
+// would have the same name as one of the struct's fields.
+func (g *generator) wantBuilders(sch *schema.Schema, fields []*Field) (bool, error) {
+	if !g.opts.Builders {
+		return false, nil
+	}
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field.Name] = true
+	}
+	for _, field := range fields {
+		if !field.Required && names[setterName(field)] {
+			return false, field.schema.Errorf("setter %s conflicts with a field of the same name", setterName(field))
+		}
+	}
+	g.addHelper(sch.Location(), helperPtr)
+	return true, nil
+}
+
+func setterName(f *Field) string {
+	return "With" + f.Name
+}
+
+// builderCode returns a constructor that takes the struct's required fields as parameters and a
+// setter for every field that isn't required.
+func (d *TypeDecl) builderCode() []jen.Code {
+	var params []jen.Code
+	values := jen.Dict{}
+	for _, f := range d.Fields {
+		if !f.Required {
+			continue
+		}
+		param := paramName(f.Name)
+		params = append(params, jen.Id(param).Add(f.Type.code()))
+		values[jen.Id(f.Name)] = jen.Id(param)
+	}
+	code := []jen.Code{
+		jen.Commentf("New%s returns a new %s with its required fields set.", d.Name, d.Name).Line().
+			Func().Id("New" + d.Name).Params(params...).Op("*").Id(d.Name).Block(
+			jen.Return(jen.Op("&").Id(d.Name).Values(values)),
+		),
+	}
+	recv := receiverName(d.Name)
+	for _, f := range d.Fields {
+		if f.Required {
+			continue
+		}
+		paramType := f.Type
+		value := jen.Id("value")
+		switch {
+		case f.Type.Kind == TypeRefPointer:
+			paramType = f.Type.Elem
+			value = jen.Op("&").Id("value")
+		case isHelperRef(f.Type, helperOptional), isHelperRef(f.Type, helperNullable):
+			paramType = f.Type.TypeArgs[0]
+			value = jen.Add(f.Type.code()).Values(jen.Dict{
+				jen.Id("Value"): jen.Id("value"),
+				jen.Id("Set"):   jen.True(),
+			})
+		}
+		code = append(code, jen.Commentf("%s sets %s and returns %s.", setterName(f), f.Name, recv).Line().
+			Func().Params(jen.Id(recv).Op("*").Id(d.Name)).Id(setterName(f)).Params(jen.Id("value").Add(paramType.code())).Op("*").Id(d.Name).Block(
+			jen.Id(recv).Dot(f.Name).Op("=").Add(value),
+			jen.Return(jen.Id(recv)),
+		))
+	}
+	return code
+}
+
+// paramName returns a parameter name for a field by lowercasing its leading initialism or first
+// letter, such as "url" for "URL" and "userID" for "UserID".
+func paramName(fieldName string) string {
+	runes := []rune(fieldName)
+	upper := 0
+	for upper < len(runes) && unicode.IsUpper(runes[upper]) {
+		upper++
+	}
+	if upper > 1 && upper < len(runes) {
+		// The last capital starts the next word, as in URLPath.
+		upper--
+	}
+	name := strings.ToLower(string(runes[:upper])) + string(runes[upper:])
+	if token.IsKeyword(name) || name == "value" {
+		name += "Value"
+	}
+	return name
+}
+
+func ptrCode() []jen.Code {
+	return []jen.Code{
+		jen.Comment("Ptr returns a pointer to v. It is useful for optional fields in struct literals."),
+		jen.Func().Id(helperPtr).Types(jen.Id("T").Any()).Params(jen.Id("v").Id("T")).Op("*").Id("T").Block(
+			jen.Return(jen.Op("&").Id("v")),
+		),
+	}
+}
@@ -0,0 +1,110 @@
+package codegen
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// ignoredKeywords are keywords that change the shape of valid data but that the generator doesn't
+// use, so the generated types don't match the schema when one of them is present.
+var ignoredKeywords = []string{
+	"allOf",
+	"anyOf",
+	"oneOf",
+	"not",
+	"if",
+	"then",
+	"else",
+	"patternProperties",
+	"dependentSchemas",
+	"prefixItems",
+	"contains",
+	"unevaluatedProperties",
+	"unevaluatedItems",
+	"propertyNames",
+	"$dynamicRef",
+	"$recursiveRef",
+}
+
+// checkKeywords records a warning for every keyword of sch that the generated type ignores. Each
+// schema is only checked once.
+func (g *generator) checkKeywords(sch *schema.Schema) error {
+	if g.checked[sch.Location()] {
+		return nil
+	}
+	g.checked[sch.Location()] = true
+	ext, err := sch.Extensions()
+	if err != nil {
+		return err
+	}
+	if ext.GoType != nil {
+		// The type is chosen by the schema author, so nothing else about the schema matters.
+		return nil
+	}
+	for _, keyword := range ignoredKeywords {
+		if _, ok := sch.Keyword(keyword); ok {
+			g.warn(sch, keyword, "%s is not supported and is ignored", keyword)
+		}
+	}
+	if value, ok := sch.Keyword("additionalProperties"); ok {
+		if _, isBool := value.(bool); !isBool {
+			g.warn(sch, "additionalProperties", "additionalProperties schemas are not supported and are ignored")
+		}
+	}
+	if value, ok := sch.Keyword("dependencies"); ok {
+		if hasSchemaDependency(value) {
+			g.warn(sch, "dependencies", "schema dependencies are not supported and are ignored")
+		}
+	}
+	if value, ok := sch.Keyword("items"); ok {
+		if _, isArray := value.([]any); isArray {
+			g.warn(sch, "items", "an array of items schemas is not supported and is ignored")
+		}
+	}
+	if value, ok := sch.Keyword("type"); ok {
+		if types, isArray := value.([]any); isArray && len(types) > 1 {
+			g.warn(sch, "type", "multiple types are not supported, only %q is used", sch.Type())
+		}
+	}
+	return nil
+}
+
+func (g *generator) warn(sch *schema.Schema, keyword, format string, args ...any) {
+	g.warnings = append(g.warnings, sch.KeywordError(keyword, fmt.Errorf(format, args...)))
+}
+
+// reportWarnings passes the warnings to Options.OnWarning or, with Options.Strict, returns them as
+// an error.
+func (g *generator) reportWarnings() error {
+	if g.opts.Strict {
+		errs := make([]error, len(g.warnings))
+		for i, warning := range g.warnings {
+			errs[i] = warning
+		}
+		return errors.Join(errs...)
+	}
+	if g.opts.OnWarning == nil {
+		return nil
+	}
+	for _, warning := range g.warnings {
+		g.opts.OnWarning(warning)
+	}
+	return nil
+}
+
+// hasSchemaDependency reports whether a draft-07 dependencies value has a schema dependency. Arrays
+// of property names are property dependencies, which only affect validation.
+func hasSchemaDependency(value any) bool {
+	dependencies, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, dependency := range dependencies {
+		if _, isArray := dependency.([]any); !isArray {
+			return true
+		}
+	}
+	return false
+}
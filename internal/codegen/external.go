@@ -0,0 +1,75 @@
+package codegen
+
+import (
+	"strings"
+
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// ExternalType is an existing Go type for schemas whose location starts with URLPrefix. References
+// to those schemas use the existing type instead of generating a new one.
+type ExternalType struct {
+	// URLPrefix is matched against absolute schema locations. The longest matching prefix wins.
+	// When Name is set, URLPrefix must match the whole location.
+	URLPrefix string
+	// Path is the import path of the package that declares the type.
+	Path string
+	// Name is the type name. When empty, the name is derived from the reference the same way a
+	// generated type would be named.
+	Name string
+}
+
+func (e *ExternalType) matches(location string) bool {
+	if e.Name != "" {
+		return strings.TrimSuffix(location, "#") == strings.TrimSuffix(e.URLPrefix, "#")
+	}
+	return strings.HasPrefix(location, e.URLPrefix)
+}
+
+// externalType returns the external type for the target of sch's $ref.
+func (g *generator) externalType(sch *schema.Schema) (*ExternalType, bool) {
+	refSchema := sch.RefSchema()
+	if refSchema == nil {
+		return nil, false
+	}
+	return g.externalTypeFor(refSchema.Location())
+}
+
+// externalTypeFor returns the external type for a schema location.
+func (g *generator) externalTypeFor(location string) (*ExternalType, bool) {
+	var found *ExternalType
+	for i := range g.opts.ExternalTypes {
+		ext := &g.opts.ExternalTypes[i]
+		if !ext.matches(location) {
+			continue
+		}
+		if found == nil || len(ext.URLPrefix) > len(found.URLPrefix) {
+			found = ext
+		}
+	}
+	return found, found != nil
+}
+
+// refTypeExpr returns a reference to the type for the target of sch's $ref. A target with an
+// x-go-type for an existing type is referenced by that type.
+func (g *generator) refTypeExpr(sch *schema.Schema) (*TypeRef, error) {
+	if refSchema := sch.RefSchema(); refSchema != nil {
+		goType, err := hasGoType(refSchema)
+		if err != nil {
+			return nil, err
+		}
+		if goType {
+			expr, _, err := g.getXGoTypeExpr(refSchema)
+			return expr, err
+		}
+	}
+	ext, ok := g.externalType(sch)
+	if !ok {
+		return g.typeRef(g.refTypeName(sch)), nil
+	}
+	typeName := ext.Name
+	if typeName == "" {
+		typeName = g.refTypeName(sch)
+	}
+	return namedRef(ext.Path, typeName), nil
+}
@@ -0,0 +1,164 @@
+package codegen
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Package is a Go package that owns the types generated from schemas whose location starts with
+// URLPrefix.
+type Package struct {
+	// URLPrefix is matched against absolute schema locations. The longest matching prefix wins.
+	URLPrefix string
+	// Path is the import path of the package.
+	Path string
+	// Name is the package name. Defaults to the last element of Path.
+	Name string
+	// Dir is the directory for the package's files relative to the output directory.
+	Dir string
+}
+
+func (p *Package) name() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return path.Base(p.Path)
+}
+
+type outputFile struct {
+	pkg  *Package
+	file *File
+}
+
+// defaultPackage returns the package for types that aren't owned by any of opts.Packages.
+func (g *generator) defaultPackage() *Package {
+	return &Package{
+		Path: g.opts.PackagePath,
+		Name: g.opts.PackageName,
+	}
+}
+
+// packageFor returns the package that owns types generated from a schema location.
+func (g *generator) packageFor(location string) *Package {
+	var found *Package
+	for i := range g.opts.Packages {
+		pkg := &g.opts.Packages[i]
+		if !strings.HasPrefix(location, pkg.URLPrefix) {
+			continue
+		}
+		if found == nil || len(pkg.URLPrefix) > len(found.URLPrefix) {
+			found = pkg
+		}
+	}
+	if found == nil {
+		return g.defaultPackage()
+	}
+	return found
+}
+
+// fileFor returns the output file for types generated from a schema location. When splitting files,
+// every schema document gets its own file. Otherwise, each package gets a single file.
+func (g *generator) fileFor(location string) *outputFile {
+	pkg := g.packageFor(location)
+	doc, _, _ := strings.Cut(location, "#")
+	key := pkg.Dir
+	if g.split {
+		key = pkg.Dir + "\x00" + doc
+	}
+	if f, ok := g.files[key]; ok {
+		return f
+	}
+	name := pkg.name() + ".go"
+	if g.split {
+		name = documentFileName(doc)
+	}
+	f := &outputFile{
+		pkg: pkg,
+		file: &File{
+			Name:        g.uniqueFileName(path.Join(pkg.Dir, name)),
+			PackageName: pkg.name(),
+			PackagePath: pkg.Path,
+			Types:       []*TypeDecl{},
+		},
+	}
+	g.files[key] = f
+	g.fileOrder = append(g.fileOrder, f)
+	return f
+}
+
+// uniqueFileName adds a numeric suffix to name when another output file already uses it.
+func (g *generator) uniqueFileName(name string) string {
+	taken := func(candidate string) bool {
+		for _, f := range g.fileOrder {
+			if f.file.Name == candidate {
+				return true
+			}
+		}
+		return false
+	}
+	if !taken(name) {
+		return name
+	}
+	base := strings.TrimSuffix(name, ".go")
+	for i := 2; ; i++ {
+		candidate := base + "_" + strconv.Itoa(i) + ".go"
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// documentFileName derives a Go file name from a schema document URL.
+func documentFileName(doc string) string {
+	name := path.Base(strings.TrimSuffix(doc, "/"))
+	name = strings.TrimSuffix(name, ".yaml")
+	name = strings.TrimSuffix(name, ".yml")
+	name = strings.TrimSuffix(name, ".json")
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" || name == "." {
+		name = "schema"
+	}
+	return name + ".go"
+}
+
+// addType adds a type declaration to the file that owns its origin and records the package that
+// owns the type so references from other packages can be qualified.
+func (g *generator) addType(decl *TypeDecl) {
+	decl.Doc = decl.schema.Description()
+	decl.Extensions = decl.schema.ExtensionKeywords()
+	f := g.fileFor(decl.Origin)
+	f.file.Types = append(f.file.Types, decl)
+	g.typePackages[decl.Name] = f.pkg.Path
+}
+
+// claimType marks typeName as generated from location before its declaration is complete so that
+// recursive references resolve to the right package.
+func (g *generator) claimType(location, typeName string) {
+	g.generatedNames[typeName] = true
+	g.typePackages[typeName] = g.packageFor(location).Path
+}
+
+// typeRef returns a reference to a generated type that is qualified when the type lives in another
+// package.
+func (g *generator) typeRef(typeName string) *TypeRef {
+	return namedRef(g.typePackages[typeName], typeName)
+}
+
+// model returns the intermediate representation of the generated files.
+func (g *generator) model() *Model {
+	model := &Model{
+		Files:         make([]*File, 0, len(g.fileOrder)),
+		ImportAliases: g.importAliases,
+	}
+	for _, f := range g.fileOrder {
+		model.Files = append(model.Files, f.file)
+	}
+	return model
+}
@@ -2,17 +2,48 @@ package codegen
 
 import (
 	"fmt"
+	"go/token"
+	"go/types"
 	"io"
+	"slices"
 	"sort"
 	"strings"
 
-	"github.com/dave/jennifer/jen"
 	"github.com/willabides/jsonschematogo/internal/schema"
 )
 
 // Options for Go code generation.
 type Options struct {
 	PackageName string
+	// PackagePath is the import path of PackageName. It is required when Packages is set so that
+	// other packages can refer to types in the default package.
+	PackagePath string
+	// Packages assigns types generated from matching schema locations to other Go packages.
+	// Only supported by GenerateFiles.
+	Packages []Package
+	// SplitFiles generates a separate file for each schema document instead of one file per package.
+	// Only supported by GenerateFiles.
+	SplitFiles bool
+	// ExternalTypes are existing Go types to use for referenced schemas instead of generating them.
+	ExternalTypes []ExternalType
+	// SourceOrder keeps struct fields in the order properties are declared in the schema instead of
+	// sorting them by name.
+	SourceOrder bool
+	// OnWarning is called for every keyword that the generated types ignore.
+	OnWarning func(*schema.Error)
+	// Strict returns the warnings as an error instead of passing them to OnWarning.
+	Strict bool
+	// Optional is how fields for properties that aren't required are represented. Default:
+	// OptionalPointer
+	Optional OptionalStrategy
+	// Getters generates getter methods for the fields of every struct. The x-go-getters extension
+	// overrides it for a struct.
+	Getters bool
+	// Builders generates a constructor that takes the required fields and setters for the other
+	// fields of every struct, along with a Ptr helper in every package.
+	Builders bool
+	// Hooks customize the rendered code. They run after the built-in hooks.
+	Hooks []Hook
 	// Schemas is a map of URI to *schema.Schema for $ref resolution
 	Schemas map[string]*schema.Schema
 }
@@ -21,12 +52,65 @@ type generator struct {
 	seenSignatures map[string]string // signature -> struct name
 	generatedNames map[string]bool
 	refNames       map[string]string
-	file           *jen.File
+	nameOwners     map[string]string // registered type name -> schema location
+	typePackages   map[string]string // type name -> import path
+	importAliases  map[string]string // import path -> alias
+	files          map[string]*outputFile
+	fileOrder      []*outputFile
+	split          bool
+	checked        map[string]bool // schema location -> keywords checked
+	warnings       []*schema.Error
 	opts           Options
 }
 
 // GenerateGoCode generates Go code from a parsed schema model and all referenced schemas.
 func GenerateGoCode(w io.Writer, sch *schema.Schema, opts *Options) error {
+	return GenerateMultiGoCode(w, []*schema.Schema{sch}, opts)
+}
+
+// GenerateMultiGoCode generates a single Go file from several entry schemas. All entry schemas share
+// one set of type names, so types that are reachable from more than one entry schema are only
+// generated once.
+func GenerateMultiGoCode(w io.Writer, schemas []*schema.Schema, opts *Options) error {
+	if opts != nil && (len(opts.Packages) > 0 || opts.SplitFiles) {
+		return fmt.Errorf("packages and split files require GenerateFiles")
+	}
+	model, err := BuildModel(schemas, opts)
+	if err != nil {
+		return err
+	}
+	hooks := slices.Clone(builtinHooks)
+	if opts != nil {
+		hooks = append(hooks, opts.Hooks...)
+	}
+	return renderModelFile(w, model, model.Files[0], hooks)
+}
+
+// GenerateFiles generates Go files from several entry schemas. The result maps file paths relative
+// to the output directory to their content.
+func GenerateFiles(schemas []*schema.Schema, opts *Options) (map[string][]byte, error) {
+	model, err := BuildModel(schemas, opts)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []Hook
+	if opts != nil {
+		hooks = opts.Hooks
+	}
+	return RenderModel(model, hooks)
+}
+
+// BuildModel builds the intermediate representation of the Go code for several entry schemas
+// without rendering it.
+func BuildModel(schemas []*schema.Schema, opts *Options) (*Model, error) {
+	g, err := generate(schemas, opts)
+	if err != nil {
+		return nil, err
+	}
+	return g.model(), nil
+}
+
+func generate(schemas []*schema.Schema, opts *Options) (*generator, error) {
 	if opts == nil {
 		opts = &Options{PackageName: "gen"}
 	}
@@ -34,40 +118,134 @@ func GenerateGoCode(w io.Writer, sch *schema.Schema, opts *Options) error {
 		opts.Schemas = map[string]*schema.Schema{}
 	}
 
-	file := jen.NewFile(opts.PackageName)
-	file.HeaderComment("Code generated by jsonschematogo. DO NOT EDIT.")
-
 	g := &generator{
 		seenSignatures: map[string]string{},
 		generatedNames: map[string]bool{},
 		refNames:       map[string]string{},
-		file:           file,
+		nameOwners:     map[string]string{},
+		typePackages:   map[string]string{},
+		importAliases:  map[string]string{},
+		files:          map[string]*outputFile{},
+		split:          opts.SplitFiles,
+		checked:        map[string]bool{},
 		opts:           *opts,
 	}
+	if !g.split {
+		// The default package always gets a file, even when it has no types.
+		g.fileFor("")
+	}
+
+	for _, sch := range schemas {
+		err := g.registerNames(sch)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	for _, sch := range schemas {
+		err := g.generateEntrySchema(sch)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := g.reportWarnings()
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// registerNames assigns type names to an entry schema and its definitions so that references
+// from any entry schema resolve to the same names. A definition whose name is already used by
+// another schema is prefixed with the name of its entry schema. Entry schemas with the same name
+// are an error. Schemas with x-go-type aren't named because no type is declared for them.
+func (g *generator) registerNames(sch *schema.Schema) error {
+	structName := ""
+	goType, err := hasGoType(sch)
+	if err != nil {
+		return err
+	}
+	if sch.Location() != "" && !goType {
+		structName, err = g.registerEntryName(sch)
+		if err != nil {
+			return err
+		}
+	}
 	for definition := range sch.OrderedDefinitions() {
+		location := definition.Schema.Location()
+		if _, ok := g.refNames[location]; ok {
+			continue
+		}
+		goType, err = hasGoType(definition.Schema)
+		if err != nil {
+			return fmt.Errorf("name definition %q: %w", definition.Name, err)
+		}
+		if goType {
+			continue
+		}
 		definitionName, err := namedSchemaName(definition.Schema, definition.Name)
 		if err != nil {
 			return fmt.Errorf("name definition %q: %w", definition.Name, err)
 		}
-		g.refNames[definition.Ref] = definitionName
-		g.refNames[definition.Schema.Location()] = definitionName
+		if _, taken := g.nameOwners[definitionName]; taken && structName != "" {
+			definitionName = structName + definitionName
+		}
+		err = g.registerName(location, definitionName)
+		if err != nil {
+			return fmt.Errorf("name definition %q: %w", definition.Name, err)
+		}
+	}
+	return nil
+}
+
+func (g *generator) registerEntryName(sch *schema.Schema) (string, error) {
+	if structName, ok := g.refNames[sch.Location()]; ok {
+		return structName, nil
+	}
+	structName, err := getStructName(sch)
+	if err != nil {
+		return "", fmt.Errorf("name schema %q: %w", sch.Location(), err)
+	}
+	err = g.registerName(sch.Location(), structName)
+	if err != nil {
+		return "", fmt.Errorf("name schema %q: %w", sch.Location(), err)
+	}
+	return structName, nil
+}
+
+// registerName assigns typeName to the schema at location. It is an error when another schema
+// already has the name.
+func (g *generator) registerName(location, typeName string) error {
+	if owner, ok := g.nameOwners[typeName]; ok && owner != location {
+		return fmt.Errorf("type name %s is already used by %s; set x-go-type-name to rename one of them", typeName, owner)
 	}
+	g.nameOwners[typeName] = location
+	g.refNames[location] = typeName
+	return nil
+}
 
+func (g *generator) generateEntrySchema(sch *schema.Schema) error {
 	for definition := range sch.OrderedDefinitions() {
-		definitionName := g.refTypeName(definition.Ref)
+		if _, ok := g.externalTypeFor(definition.Schema.Location()); ok {
+			continue
+		}
+		definitionName := g.refNames[definition.Schema.Location()]
 		err := g.generateNamedSchema(definition.Schema, definitionName, false)
 		if err != nil {
 			return fmt.Errorf("generate definition %q: %w", definition.Name, err)
 		}
 	}
 
-	err := g.generateStruct(sch, "")
+	goType, err := hasGoType(sch)
+	if err != nil || goType {
+		return err
+	}
+	// Entry schemas are never deduplicated by signature because each of them was asked for by name.
+	err = g.generateStructWithOptions(sch, g.refNames[sch.Location()], false)
 	if err != nil {
 		return fmt.Errorf("generate struct: %w", err)
 	}
-
-	return file.Render(w)
+	return nil
 }
 
 // generateStruct generates a Go struct for a schema and all referenced/inline schemas.
@@ -83,12 +261,20 @@ func (g *generator) generateNamedSchema(
 	if g.generatedNames[typeName] {
 		return nil
 	}
+	goType, err := hasGoType(sch)
+	if err != nil || goType {
+		return err
+	}
+	err = g.checkKeywords(sch)
+	if err != nil {
+		return err
+	}
 	if sch.Type() == "object" && sch.HasProperties() {
 		return g.generateStructWithOptions(sch, typeName, deduplicateObjects)
 	}
 
-	g.generatedNames[typeName] = true
-	err := g.generateSchemaDependencies(sch, typeName)
+	g.claimType(sch.Location(), typeName)
+	err = g.generateSchemaDependencies(sch, typeName)
 	if err != nil {
 		return err
 	}
@@ -96,15 +282,20 @@ func (g *generator) generateNamedSchema(
 	if err != nil {
 		return err
 	}
-	g.file.Type().Id(typeName).Add(typeExpr)
-	g.file.Line()
+	g.addType(&TypeDecl{
+		Name:   typeName,
+		Kind:   TypeDeclDefined,
+		Origin: sch.Location(),
+		Type:   typeExpr,
+		schema: sch,
+	})
 	return nil
 }
 
 func (g *generator) generateSchemaDependencies(sch *schema.Schema, typeName string) error {
 	refSchema := sch.RefSchema()
 	if refSchema != nil {
-		err := g.generateReferencedSchema(sch.Ref(), refSchema)
+		err := g.generateReferencedSchema(sch, refSchema)
 		if err != nil {
 			return err
 		}
@@ -115,24 +306,24 @@ func (g *generator) generateSchemaDependencies(sch *schema.Schema, typeName stri
 	return g.generateArrayItemTypes(sch.Items(), typeName, "")
 }
 
-func (g *generator) namedSchemaTypeExpr(sch *schema.Schema, typeName string) (jen.Code, error) {
+func (g *generator) namedSchemaTypeExpr(sch *schema.Schema, typeName string) (*TypeRef, error) {
 	switch {
 	case sch.Ref() != "":
-		return jen.Id(g.refTypeName(sch.Ref())), nil
+		return g.refTypeExpr(sch)
 	case sch.Type() == "array":
 		items := sch.Items()
 		if items == nil {
-			return jen.Index().Interface(), nil
+			return sliceOf(interfaceRef()), nil
 		}
 		itemExpr, err := g.getArrayItemExpr(items, typeName, "")
 		if err != nil {
 			return nil, err
 		}
-		return jen.Index().Add(itemExpr), nil
+		return sliceOf(itemExpr), nil
 	case sch.Type() == "object":
-		return jen.Map(jen.String()).Interface(), nil
+		return mapOf(interfaceRef()), nil
 	default:
-		return jen.Id(getPrimitiveGoType(sch.Type())), nil
+		return namedRef("", getPrimitiveGoType(sch.Type())), nil
 	}
 }
 
@@ -141,41 +332,47 @@ func (g *generator) generateStructWithOptions(
 	structName string,
 	deduplicateBySignature bool,
 ) error {
+	err := g.checkKeywords(sch)
+	if err != nil {
+		return err
+	}
 	signature := structSignature(sch)
 	if deduplicateBySignature {
 		if _, ok := g.seenSignatures[signature]; ok {
 			return nil
 		}
 	}
-	if structName != "" && g.generatedNames[structName] {
-		return nil
-	}
 	if structName == "" {
-		var err error
 		structName, err = getStructName(sch)
 		if err != nil {
 			return err
 		}
 	}
+	if g.generatedNames[structName] {
+		return nil
+	}
 	if _, ok := g.seenSignatures[signature]; !ok {
 		g.seenSignatures[signature] = structName
 	}
-	g.generatedNames[structName] = true
+	g.claimType(sch.Location(), structName)
 
-	type field struct {
-		name string
-		stmt *jen.Statement
+	properties := sch.OrderedProperties()
+	if g.opts.SourceOrder {
+		properties = sch.SourceOrderedProperties()
 	}
-
-	var fields []field
-	for propName, prop := range sch.OrderedProperties() {
+	fields := []*Field{}
+	for propName, prop := range properties {
+		err = g.checkKeywords(prop)
+		if err != nil {
+			return err
+		}
 		propExt, err := prop.Extensions()
 		if err != nil {
 			return err
 		}
 		refSchema := prop.RefSchema()
 		if refSchema != nil {
-			err = g.generateReferencedSchema(prop.Ref(), refSchema)
+			err = g.generateReferencedSchema(prop, refSchema)
 			if err != nil {
 				return err
 			}
@@ -198,21 +395,30 @@ func (g *generator) generateStructWithOptions(
 				return err
 			}
 		}
-		fieldStmt, err := g.generateField(propName, structName, prop, sch)
+		field, err := g.generateField(propName, structName, prop, sch)
 		if err != nil {
 			return err
 		}
-		fields = append(fields, field{name: propName, stmt: fieldStmt})
+		fields = append(fields, field)
 	}
 
-	// Create the struct
-	var fieldCodes []jen.Code
-	for _, f := range fields {
-		fieldCodes = append(fieldCodes, f.stmt)
+	getters, err := g.wantGetters(sch, fields)
+	if err != nil {
+		return err
+	}
+	builders, err := g.wantBuilders(sch, fields)
+	if err != nil {
+		return err
 	}
-	structDef := jen.Type().Id(structName).Struct(fieldCodes...)
-	g.file.Add(structDef)
-	g.file.Line()
+	g.addType(&TypeDecl{
+		Name:     structName,
+		Kind:     TypeDeclStruct,
+		Origin:   sch.Location(),
+		Fields:   fields,
+		Getters:  getters,
+		Builders: builders,
+		schema:   sch,
+	})
 	return nil
 }
 
@@ -225,16 +431,21 @@ func (g *generator) generateArrayItemTypes(items *schema.Schema, structName, pro
 	if items == nil {
 		return nil
 	}
+	err := g.checkKeywords(items)
+	if err != nil {
+		return err
+	}
 	refSchema := items.RefSchema()
 	if refSchema != nil {
-		err := g.generateReferencedSchema(items.Ref(), refSchema)
+		err = g.generateReferencedSchema(items, refSchema)
 		if err != nil {
 			return err
 		}
 	}
 	if items.Type() == "object" && items.HasProperties() {
 		inlineName := structName + capitalizeFirst(propName) + "ItemObject"
-		ext, err := items.Extensions()
+		var ext *schema.Extensions
+		ext, err = items.Extensions()
 		if err != nil {
 			return err
 		}
@@ -249,8 +460,11 @@ func (g *generator) generateArrayItemTypes(items *schema.Schema, structName, pro
 	return nil
 }
 
-func (g *generator) generateReferencedSchema(ref string, sch *schema.Schema) error {
-	typeName := g.refTypeName(ref)
+func (g *generator) generateReferencedSchema(refSource, sch *schema.Schema) error {
+	if _, ok := g.externalType(refSource); ok {
+		return nil
+	}
+	typeName := g.refTypeName(refSource)
 	return g.generateNamedSchema(sch, typeName, true)
 }
 
@@ -280,7 +494,7 @@ func structSignature(sch *schema.Schema) string {
 }
 
 // generateField generates a Go struct field for a property.
-func (g *generator) generateField(name, parentName string, prop, parent *schema.Schema) (*jen.Statement, error) {
+func (g *generator) generateField(name, parentName string, prop, parent *schema.Schema) (*Field, error) {
 	fieldName := toGoFieldName(name)
 	ext, err := prop.Extensions()
 	if err == nil && ext.GoName != nil {
@@ -288,93 +502,101 @@ func (g *generator) generateField(name, parentName string, prop, parent *schema.
 	}
 	isRequired := parent.IsPropertyRequired(name)
 
-	typeExpr, err := g.goTypeExpr(prop, parentName, name, isRequired)
+	typeExpr, err := g.goTypeExpr(prop, parentName, name)
 	if err != nil {
 		return nil, err
 	}
-	return jen.Id(fieldName).Add(typeExpr).Tag(map[string]string{"json": name}), nil
+	omitZero := false
+	if !isRequired {
+		typeExpr, omitZero, err = g.optionalTypeExpr(prop, parent, typeExpr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defaultValue, ok := prop.Keyword("default")
+	if refSchema := prop.RefSchema(); !ok && refSchema != nil {
+		defaultValue, _ = refSchema.Keyword("default")
+	}
+	return &Field{
+		Name:       fieldName,
+		JSONName:   name,
+		Type:       typeExpr,
+		Required:   isRequired,
+		OmitZero:   omitZero,
+		Origin:     prop.Location(),
+		Doc:        prop.Description(),
+		Extensions: prop.ExtensionKeywords(),
+		Default:    defaultValue,
+		schema:     prop,
+	}, nil
 }
 
-// isBuiltinType checks if a type name is a built-in type.
-func isBuiltinType(typeName string) bool {
-	switch typeName {
-	case "int", "string", "float64", "bool", "any":
-		return true
+// hasGoType reports whether sch has an x-go-type that refers to an existing type. No type is
+// declared for such a schema, and references to it use the x-go-type instead. An x-go-type that is
+// a plain identifier names the type that is declared for the schema.
+func hasGoType(sch *schema.Schema) (bool, error) {
+	ext, err := sch.Extensions()
+	if err != nil {
+		return false, err
 	}
-	return false
+	return ext.GoType != nil && !isDeclaredName(*ext.GoType), nil
 }
 
-// getXGoTypeExpr handles x-go-type with optional import extensions.
-func (g *generator) getXGoTypeExpr(prop *schema.Schema, isRequired bool) (jen.Code, bool, error) {
+// isDeclaredName reports whether x-go-type on a schema is the name of the type to declare for it
+// rather than an existing type.
+func isDeclaredName(goType string) bool {
+	return token.IsIdentifier(goType) && types.Universe.Lookup(goType) == nil
+}
+
+// getXGoTypeExpr parses x-go-type and resolves its package qualifiers with x-go-type-import.
+func (g *generator) getXGoTypeExpr(prop *schema.Schema) (*TypeRef, bool, error) {
 	ext, err := prop.Extensions()
 	if err != nil {
 		return nil, false, err
 	}
-	stmt := &jen.Statement{}
 	if ext.GoType == nil {
 		return nil, false, nil
 	}
-
-	if !isRequired {
-		stmt.Op("*")
-	}
-
-	typeName := *ext.GoType
-	qualPath := ""
-
-	if ext.GoTypeImport != nil {
-		parts := strings.Split(typeName, ".")
-		typeName = parts[len(parts)-1]
-		qualPath = ext.GoTypeImport.Path
-		if ext.GoTypeImport.Name != "" && qualPath != "" {
-			g.file.ImportAlias(qualPath, ext.GoTypeImport.Name)
-		}
+	ref, err := g.parseGoType(*ext.GoType, ext.GoTypeImport)
+	if err != nil {
+		return nil, false, prop.KeywordError("x-go-type", err)
 	}
-
-	return stmt.Qual(qualPath, typeName), true, nil
+	return ref, true, nil
 }
 
 // getArrayItemExpr handles array item type expressions.
 func (g *generator) getArrayItemExpr(
 	items *schema.Schema,
 	parentName, propName string,
-) (jen.Code, error) {
+) (*TypeRef, error) {
+	expr, ok, err := g.getXGoTypeExpr(items)
+	if err != nil || ok {
+		return expr, err
+	}
 	ext, err := items.Extensions()
 	if err != nil {
 		return nil, err
 	}
 	switch {
-	case ext.GoType != nil:
-		// Check if array items have import extension
-		if importPath, _, hasImport := items.GetImportExtension(); hasImport {
-			// Extract just the type name from the full qualified name
-			typeName := *ext.GoType
-			if strings.Contains(*ext.GoType, ".") {
-				parts := strings.Split(*ext.GoType, ".")
-				typeName = parts[len(parts)-1]
-			}
-			return jen.Qual(importPath, typeName), nil
-		}
-		return jen.Id(*ext.GoType), nil
 	case items.Ref() != "":
-		return jen.Id(g.refTypeName(items.Ref())), nil
+		return g.refTypeExpr(items)
 	case items.Type() == "object" && items.HasProperties():
 		inlineName := parentName + capitalizeFirst(propName) + "ItemObject"
 		if ext.GoTypeName != nil {
 			inlineName = *ext.GoTypeName
 		}
-		return jen.Id(inlineName), nil
+		return g.typeRef(inlineName), nil
 	case items.Type() == "object":
-		return jen.Map(jen.String()).Interface(), nil
+		return mapOf(interfaceRef()), nil
 	default:
-		return jen.Id(getPrimitiveGoType(items.Type())), nil
+		return namedRef("", getPrimitiveGoType(items.Type())), nil
 	}
 }
 
-// goTypeExpr builds a jen.Code type expression for a schema property.
-func (g *generator) goTypeExpr(prop *schema.Schema, parentName, propName string, isRequired bool) (jen.Code, error) {
+// goTypeExpr builds the type expression for a schema property when it is required.
+func (g *generator) goTypeExpr(prop *schema.Schema, parentName, propName string) (*TypeRef, error) {
 	// Handle x-go-type first
-	expr, ok, err := g.getXGoTypeExpr(prop, isRequired)
+	expr, ok, err := g.getXGoTypeExpr(prop)
 	if err != nil {
 		return nil, err
 	}
@@ -383,47 +605,35 @@ func (g *generator) goTypeExpr(prop *schema.Schema, parentName, propName string,
 	}
 
 	if prop.Ref() != "" {
-		refName := g.refTypeName(prop.Ref())
-		if !isRequired {
-			return jen.Op("*").Id(refName), nil
-		}
-		return jen.Id(refName), nil
+		return g.refTypeExpr(prop)
 	}
 
 	if prop.Type() == "array" {
 		items := prop.Items()
 		if items == nil {
-			return jen.Index().Interface(), nil
+			return sliceOf(interfaceRef()), nil
 		}
-		var itemExpr jen.Code
+		var itemExpr *TypeRef
 		itemExpr, err = g.getArrayItemExpr(items, parentName, propName)
 		if err != nil {
 			return nil, err
 		}
-		return jen.Index().Add(itemExpr), nil
+		return sliceOf(itemExpr), nil
 	}
 
 	if prop.Type() == "object" {
-		return g.goTypeObjectExpr(prop, parentName, propName, isRequired)
+		return g.goTypeObjectExpr(prop, parentName, propName)
 	}
 
-	typeName := getPrimitiveGoType(prop.Type())
-	if !isRequired && !isSliceOrMapType(typeName) {
-		if isBuiltinType(typeName) {
-			return jen.Id("*" + typeName), nil
-		}
-		return jen.Op("*").Id(typeName), nil
-	}
-	return jen.Id(typeName), nil
+	return namedRef("", getPrimitiveGoType(prop.Type())), nil
 }
 
 func (g *generator) goTypeObjectExpr(
 	prop *schema.Schema,
 	parentName, propName string,
-	isRequired bool,
-) (jen.Code, error) {
+) (*TypeRef, error) {
 	if !prop.HasProperties() {
-		return jen.Map(jen.String()).Interface(), nil
+		return mapOf(interfaceRef()), nil
 	}
 	inlineName := parentName + capitalizeFirst(propName) + "Object"
 	ext, err := prop.Extensions()
@@ -433,13 +643,18 @@ func (g *generator) goTypeObjectExpr(
 	if ext.GoTypeName != nil {
 		inlineName = *ext.GoTypeName
 	}
-	if !isRequired {
-		return jen.Op("*").Id(inlineName), nil
-	}
-	return jen.Id(inlineName), nil
+	return g.typeRef(inlineName), nil
 }
 
-func (g *generator) refTypeName(ref string) string {
+// refTypeName returns the type name for the target of sch's $ref.
+func (g *generator) refTypeName(sch *schema.Schema) string {
+	refSchema := sch.RefSchema()
+	if refSchema != nil {
+		if typeName, ok := g.refNames[refSchema.Location()]; ok {
+			return typeName
+		}
+	}
+	ref := sch.Ref()
 	if typeName, ok := g.refNames[ref]; ok {
 		return typeName
 	}
@@ -462,13 +677,13 @@ func namedSchemaName(sch *schema.Schema, fallback string) (string, error) {
 	if ext.GoTypeName != nil {
 		return *ext.GoTypeName, nil
 	}
-	if ext.GoType != nil {
+	if ext.GoType != nil && isDeclaredName(*ext.GoType) {
 		return *ext.GoType, nil
 	}
 	return toGoFieldName(fallback), nil
 }
 
-// getStructName gets the struct name from x-go-type-name, x-go-type extension or infers it.
+// getStructName gets the struct name from the x-go-type-name or x-go-type extension or infers it.
 func getStructName(sch *schema.Schema) (string, error) {
 	ext, err := sch.Extensions()
 	if err != nil {
@@ -477,7 +692,7 @@ func getStructName(sch *schema.Schema) (string, error) {
 	if ext.GoTypeName != nil {
 		return *ext.GoTypeName, nil
 	}
-	if ext.GoType != nil {
+	if ext.GoType != nil && isDeclaredName(*ext.GoType) {
 		return *ext.GoType, nil
 	}
 
@@ -556,8 +771,3 @@ func toGoFieldName(s string) string {
 
 	return strings.Join(parts, "")
 }
-
-// isSliceOrMapType checks if a Go type is a slice or map.
-func isSliceOrMapType(goType string) bool {
-	return strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[")
-}
@@ -1,6 +1,7 @@
 package codegen_test
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -42,6 +43,8 @@ func TestCodegen(t *testing.T) {
 	for _, test := range []struct {
 		name string
 		file string
+		// args are additional command line arguments
+		args []string
 	}{
 		{
 			name: "Primitives",
@@ -143,6 +146,10 @@ func TestCodegen(t *testing.T) {
 			name: "XGoName",
 			file: "testdata/schemas/x_go_name.yaml",
 		},
+		{
+			name: "XGoTags",
+			file: "testdata/schemas/x_go_tags.yaml",
+		},
 		{
 			name: "XGoTypeName",
 			file: "testdata/schemas/x_go_type_name.yaml",
@@ -163,9 +170,175 @@ func TestCodegen(t *testing.T) {
 			name: "NoSchemaDraft",
 			file: "testdata/schemas/no_schema_draft.yaml",
 		},
+		{
+			name: "MultipleEntrySchemas",
+			file: "testdata/schemas/company/company.yaml",
+			args: []string{"testdata/schemas/company/person.yaml"},
+		},
+		{
+			name: "SharedDefinitions",
+			file: "testdata/schemas/multi/order.yaml",
+			args: []string{"testdata/schemas/multi/customer.yaml"},
+		},
+		{
+			name: "SameSignatureEntrySchemas",
+			file: "testdata/schemas/collisions/person.yaml",
+			args: []string{"testdata/schemas/collisions/employee.yaml"},
+		},
+		{
+			name: "SameDefinitionNames",
+			file: "testdata/schemas/collisions/order.yaml",
+			args: []string{"testdata/schemas/collisions/invoice.yaml"},
+		},
+		{
+			name: "Directory",
+			file: "testdata/schemas/multi",
+		},
+		{
+			name: "GlobPattern",
+			file: "testdata/schemas/**/company/*.yaml",
+			args: []string{"--exclude", "**/person.yaml"},
+		},
+		{
+			name: "TypeMap",
+			file: "testdata/schemas/multi/order.yaml",
+			args: []string{"--type-map", "testdata/schemas/multi/customer.yaml=example.com/shared.Client"},
+		},
+		{
+			name: "PropertyOrder",
+			file: "testdata/schemas/property_order.yaml",
+		},
+		{
+			name: "PropertyOrderSource",
+			file: "testdata/schemas/property_order.yaml",
+			args: []string{"--property-order", "source"},
+		},
+		{
+			name: "PropertyOrderSourceJSON",
+			file: "testdata/schemas/property_order.json",
+			args: []string{"--property-order", "source"},
+		},
+		{
+			name: "UnsupportedKeywords",
+			file: "testdata/schemas/unsupported_keywords.yaml",
+		},
+		{
+			name: "OptionalStrategies",
+			file: "testdata/schemas/optional_strategies.yaml",
+		},
+		{
+			name: "OptionalOmitZero",
+			file: "testdata/schemas/optional_strategies.yaml",
+			args: []string{"--optional", "omitzero"},
+		},
+		{
+			name: "OptionalGeneric",
+			file: "testdata/schemas/optional_strategies.yaml",
+			args: []string{"--optional", "optional"},
+		},
+		{
+			name: "OptionalNullable",
+			file: "testdata/schemas/optional_strategies.yaml",
+			args: []string{"--optional", "nullable"},
+		},
+		{
+			name: "Getters",
+			file: "testdata/schemas/getters.yaml",
+			args: []string{"--getters"},
+		},
+		{
+			name: "GettersNullable",
+			file: "testdata/schemas/getters.yaml",
+			args: []string{"--getters", "--optional", "nullable"},
+		},
+		{
+			name: "Builders",
+			file: "testdata/schemas/builders.yaml",
+			args: []string{"--builders"},
+		},
+		{
+			name: "BuildersOptional",
+			file: "testdata/schemas/builders.yaml",
+			args: []string{"--builders", "--optional", "optional"},
+		},
+		{
+			name: "XGoTypeExpressions",
+			file: "testdata/schemas/x_go_type_expressions.yaml",
+		},
+		{
+			name: "XGoTypeDefinitions",
+			file: "testdata/schemas/x_go_type_definitions.yaml",
+		},
+		{
+			name: "TypeMapPrefix",
+			file: "testdata/schemas/multi/order.yaml",
+			args: []string{"--type-map", "testdata/schemas/multi/customer.yaml=example.com/shared"},
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			testCodegen(t, test.file)
+			testCodegen(t, append([]string{test.file}, test.args...)...)
+		})
+	}
+}
+
+func testCodegenOutputDir(t *testing.T, args ...string) {
+	outputDir := t.TempDir()
+	runResult := testrun.Run(append([]string{"--output-dir", outputDir}, args...)...)
+	runResultYaml, err := yaml.Marshal(normalizeRunResult(runResult))
+	require.NoError(t, err)
+	testutil.AssertGolden(t, runResultYaml, &testutil.AssertGoldenOptions{
+		GoldenfileSuffix: "/run_result.yaml",
+	})
+	err = filepath.WalkDir(outputDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(outputDir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		testutil.AssertGolden(t, content, &testutil.AssertGoldenOptions{
+			GoldenfileSuffix: "/" + filepath.ToSlash(rel),
+		})
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestCodegenOutputDir(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "SplitFiles",
+			args: []string{
+				"testdata/schemas/multi/order.yaml",
+				"testdata/schemas/multi/customer.yaml",
+			},
+		},
+		{
+			name: "PackageMap",
+			args: []string{
+				"--import-path", "example.com/gen",
+				"--package-map", "testdata/schemas/multi/customer.yaml=customers",
+				"testdata/schemas/multi/order.yaml",
+			},
+		},
+		{
+			name: "PackageMapWithoutImportPath",
+			args: []string{
+				"--package-map", "testdata/schemas/multi/customer.yaml=customers",
+				"testdata/schemas/multi/order.yaml",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			testCodegenOutputDir(t, test.args...)
 		})
 	}
 }
@@ -174,6 +347,7 @@ func TestCodegenErrors(t *testing.T) {
 	for _, test := range []struct {
 		name        string
 		file        string
+		args        []string
 		expectError bool
 	}{
 		{
@@ -221,16 +395,66 @@ func TestCodegenErrors(t *testing.T) {
 			file:        "testdata/schemas/invalid_ref.yaml",
 			expectError: true,
 		},
+		{
+			name:        "InvalidExtension",
+			file:        "testdata/schemas/invalid_extension.yaml",
+			expectError: true,
+		},
+		{
+			name:        "InvalidGoName",
+			file:        "testdata/schemas/invalid_go_name.json",
+			expectError: true,
+		},
+		{
+			name:        "InvalidXGoOptional",
+			file:        "testdata/schemas/invalid_x_go_optional.yaml",
+			expectError: true,
+		},
+		{
+			name:        "GetterConflict",
+			file:        "testdata/schemas/getter_conflict.yaml",
+			args:        []string{"--getters"},
+			expectError: true,
+		},
+		{
+			name:        "SetterConflict",
+			file:        "testdata/schemas/setter_conflict.yaml",
+			args:        []string{"--builders"},
+			expectError: true,
+		},
+		{
+			name:        "InvalidXGoType",
+			file:        "testdata/schemas/invalid_x_go_type.yaml",
+			expectError: true,
+		},
+		{
+			name:        "XGoTypeUnmatchedImport",
+			file:        "testdata/schemas/x_go_type_unmatched_import.yaml",
+			expectError: true,
+		},
+		{
+			name:        "SameEntrySchemaNames",
+			file:        "testdata/schemas/collisions/b/person.yaml",
+			args:        []string{"testdata/schemas/collisions/a/person.yaml"},
+			expectError: true,
+		},
+		{
+			name:        "Strict",
+			file:        "testdata/schemas/unsupported_keywords.yaml",
+			args:        []string{"--strict"},
+			expectError: true,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			testCodegenError(t, test.file, test.expectError)
+			testCodegenError(t, test.file, test.expectError, test.args...)
 		})
 	}
 }
 
-func testCodegenError(t *testing.T, file string, expectError bool) {
+func testCodegenError(t *testing.T, file string, expectError bool, extraArgs ...string) {
 	goOutputFile := t.TempDir() + "/output.go"
 	args := []string{"--output", goOutputFile}
+	args = append(args, extraArgs...)
 	args = append(args, file)
 	runResult := testrun.Run(args...)
 
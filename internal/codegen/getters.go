@@ -0,0 +1,219 @@
+package codegen
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// wantGetters reports whether getters are generated for the struct generated from sch. The
+// x-go-getters extension overrides Options.Getters. It returns an error when a getter would have
+// the same name as one of the struct's fields.
+func (g *generator) wantGetters(sch *schema.Schema, fields []*Field) (bool, error) {
+	ext, err := sch.Extensions()
+	if err != nil {
+		return false, err
+	}
+	want := g.opts.Getters
+	if ext.GoGetters != nil {
+		want = *ext.GoGetters
+	}
+	if !want {
+		return false, nil
+	}
+	names := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		names[field.Name] = true
+	}
+	for _, field := range fields {
+		if names[getterName(field)] {
+			return false, field.schema.Errorf("getter %s conflicts with a field of the same name", getterName(field))
+		}
+	}
+	return true, nil
+}
+
+func getterName(f *Field) string {
+	return "Get" + f.Name
+}
+
+// typeIndex maps package path and name to the type declarations of a model.
+type typeIndex map[[2]string]*TypeDecl
+
+func newTypeIndex(model *Model) typeIndex {
+	types := typeIndex{}
+	for _, f := range model.Files {
+		for _, decl := range f.Types {
+			types[[2]string{f.PackagePath, decl.Name}] = decl
+		}
+	}
+	return types
+}
+
+// lookup returns the declaration of a named type generated in the model.
+func (types typeIndex) lookup(t *TypeRef) *TypeDecl {
+	if t.Kind != TypeRefNamed || len(t.TypeArgs) > 0 {
+		return nil
+	}
+	return types[[2]string{t.Package, t.Name}]
+}
+
+// underlying follows defined types in the model to the type they are defined as.
+func (types typeIndex) underlying(t *TypeRef) *TypeRef {
+	for range len(types) + 1 {
+		decl := types.lookup(t)
+		if decl == nil || decl.Kind != TypeDeclDefined {
+			return t
+		}
+		t = decl.Type
+	}
+	return t
+}
+
+// getterCode returns a getter method for every field of a struct. Getters are safe to call on a
+// nil receiver and return the property's default or the zero value when the property is absent.
+// Fields that point to generated structs return the pointer so that getters can be chained.
+func (d *TypeDecl) getterCode(types typeIndex) []jen.Code {
+	recv := receiverName(d.Name)
+	code := make([]jen.Code, 0, len(d.Fields))
+	for _, f := range d.Fields {
+		field := jen.Id(recv).Dot(f.Name)
+		absent := jen.Id(recv).Op("==").Nil()
+		resultType := f.Type
+		value := field.Clone()
+		switch {
+		case f.Type.Kind == TypeRefPointer && isStruct(types.lookup(f.Type.Elem)):
+			// Returning the pointer keeps chained getters such as GetA().GetB() nil-safe.
+		case f.Type.Kind == TypeRefPointer:
+			resultType = f.Type.Elem
+			absent = absent.Op("||").Add(field.Clone()).Op("==").Nil()
+			value = jen.Op("*").Add(field.Clone())
+		case isHelperRef(f.Type, helperOptional):
+			resultType = f.Type.TypeArgs[0]
+			absent = absent.Op("||").Op("!").Add(field.Clone()).Dot("Set")
+			value = field.Clone().Dot("Value")
+		case isHelperRef(f.Type, helperNullable):
+			resultType = f.Type.TypeArgs[0]
+			absent = absent.Op("||").Op("!").Add(field.Clone()).Dot("Set").Op("||").Add(field.Clone()).Dot("Null")
+			value = field.Clone().Dot("Value")
+		}
+		fallback := zeroReturn(resultType, types)
+		fallbackDoc := "the zero value"
+		if lit := defaultLit(f.Default, types.underlying(resultType)); lit != nil {
+			fallback = []jen.Code{jen.Return(lit)}
+			fallbackDoc = "its default"
+		}
+		code = append(code, jen.Commentf("%s returns %s, or %s when it is absent.", getterName(f), f.Name, fallbackDoc).Line().
+			Func().Params(jen.Id(recv).Op("*").Id(d.Name)).Id(getterName(f)).Params().Add(resultType.code()).Block(
+			jen.If(absent).Block(fallback...),
+			jen.Return(value),
+		))
+	}
+	return code
+}
+
+func isStruct(decl *TypeDecl) bool {
+	return decl != nil && decl.Kind == TypeDeclStruct
+}
+
+func isHelperRef(t *TypeRef, helper string) bool {
+	return t.Kind == TypeRefNamed && t.Name == helper && len(t.TypeArgs) == 1
+}
+
+// receiverName returns the receiver name for methods of typeName.
+func receiverName(typeName string) string {
+	for _, r := range typeName {
+		return string(unicode.ToLower(r))
+	}
+	return "t"
+}
+
+// zeroReturn returns statements that return the zero value of t.
+func zeroReturn(t *TypeRef, types typeIndex) []jen.Code {
+	u := types.underlying(t)
+	switch {
+	case u.Kind == TypeRefArray:
+	case u.Kind != TypeRefNamed:
+		return []jen.Code{jen.Return(jen.Nil())}
+	case isStruct(types.lookup(u)):
+		return []jen.Code{jen.Return(jen.Add(t.code()).Values())}
+	case u.Package != "":
+	case u.Name == "string":
+		return []jen.Code{jen.Return(jen.Lit(""))}
+	case u.Name == "bool":
+		return []jen.Code{jen.Return(jen.False())}
+	case isIntType(u.Name) || isFloatType(u.Name):
+		return []jen.Code{jen.Return(jen.Lit(0))}
+	}
+	return []jen.Code{
+		jen.Var().Id("zero").Add(t.code()),
+		jen.Return(jen.Id("zero")),
+	}
+}
+
+// defaultLit returns the literal for a default value of a property whose type has the underlying
+// type t. It returns nil when the value isn't a constant of that type.
+func defaultLit(value any, t *TypeRef) jen.Code {
+	if value == nil || t.Kind != TypeRefNamed || t.Package != "" {
+		return nil
+	}
+	switch v := value.(type) {
+	case string:
+		if t.Name == "string" {
+			return jen.Lit(v)
+		}
+	case bool:
+		if t.Name == "bool" {
+			return jen.Lit(v)
+		}
+	default:
+		number, ok := toFloat(value)
+		if !ok {
+			return nil
+		}
+		switch {
+		case isFloatType(t.Name):
+			return jen.Lit(number)
+		case isIntType(t.Name) && number == math.Trunc(number) && math.Abs(number) < 1<<53:
+			if number < 0 && (strings.HasPrefix(t.Name, "uint") || t.Name == "byte") {
+				return nil
+			}
+			return jen.Lit(int(number))
+		}
+	}
+	return nil
+}
+
+// toFloat converts a number decoded from a JSON or YAML document.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func isIntType(name string) bool {
+	switch name {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "byte", "rune":
+		return true
+	}
+	return false
+}
+
+func isFloatType(name string) bool {
+	return name == "float32" || name == "float64"
+}
@@ -0,0 +1,172 @@
+package codegen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// parseGoType parses an x-go-type value as a Go type expression such as "[]uuid.UUID",
+// "map[string]decimal.Decimal" or "Optional[foo.Bar]". Package qualifiers are resolved with
+// imports.
+func (g *generator) parseGoType(expr string, imports []schema.GoTypeImport) (*TypeRef, error) {
+	node, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x-go-type %q: %w", expr, err)
+	}
+	qualifiers, err := resolveImports(node, imports)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x-go-type %q: %w", expr, err)
+	}
+	ref, err := g.goTypeRef(node, qualifiers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid x-go-type %q: %w", expr, err)
+	}
+	return ref, nil
+}
+
+func (g *generator) goTypeRef(node ast.Expr, imports map[string]schema.GoTypeImport) (*TypeRef, error) {
+	switch node := node.(type) {
+	case *ast.Ident:
+		return namedRef("", node.Name), nil
+	case *ast.SelectorExpr:
+		pkg, ok := node.X.(*ast.Ident)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a type", types.ExprString(node))
+		}
+		return g.qualifiedRef(pkg.Name, node.Sel.Name, imports)
+	case *ast.ParenExpr:
+		return g.goTypeRef(node.X, imports)
+	case *ast.StarExpr:
+		elem, err := g.goTypeRef(node.X, imports)
+		if err != nil {
+			return nil, err
+		}
+		return pointerTo(elem), nil
+	case *ast.ArrayType:
+		elem, err := g.goTypeRef(node.Elt, imports)
+		if err != nil {
+			return nil, err
+		}
+		if node.Len == nil {
+			return sliceOf(elem), nil
+		}
+		lit, ok := node.Len.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, fmt.Errorf("array length %s is not an integer", types.ExprString(node.Len))
+		}
+		n, err := strconv.Atoi(lit.Value)
+		if err != nil {
+			return nil, fmt.Errorf("array length %s is not an integer", lit.Value)
+		}
+		return &TypeRef{Kind: TypeRefArray, Len: n, Elem: elem}, nil
+	case *ast.MapType:
+		key, err := g.goTypeRef(node.Key, imports)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := g.goTypeRef(node.Value, imports)
+		if err != nil {
+			return nil, err
+		}
+		ref := mapOf(elem)
+		if key.Kind != TypeRefNamed || key.Name != "string" || key.Package != "" {
+			ref.Key = key
+		}
+		return ref, nil
+	case *ast.IndexExpr:
+		return g.genericRef(node.X, []ast.Expr{node.Index}, imports)
+	case *ast.IndexListExpr:
+		return g.genericRef(node.X, node.Indices, imports)
+	case *ast.InterfaceType:
+		if len(node.Methods.List) > 0 {
+			return nil, fmt.Errorf("interfaces with methods are not supported")
+		}
+		return interfaceRef(), nil
+	default:
+		return nil, fmt.Errorf("%s is not supported", types.ExprString(node))
+	}
+}
+
+// genericRef returns a reference to an instantiated generic type.
+func (g *generator) genericRef(base ast.Expr, args []ast.Expr, imports map[string]schema.GoTypeImport) (*TypeRef, error) {
+	ref, err := g.goTypeRef(base, imports)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Kind != TypeRefNamed {
+		return nil, fmt.Errorf("%s is not a generic type", ref)
+	}
+	for _, arg := range args {
+		argRef, err := g.goTypeRef(arg, imports)
+		if err != nil {
+			return nil, err
+		}
+		ref.TypeArgs = append(ref.TypeArgs, argRef)
+	}
+	return ref, nil
+}
+
+// qualifiedRef returns a reference to pkg.name using the import that pkg resolved to. Without
+// imports, the name is used as it is written.
+func (g *generator) qualifiedRef(pkg, name string, imports map[string]schema.GoTypeImport) (*TypeRef, error) {
+	if imports == nil {
+		return namedRef("", pkg+"."+name), nil
+	}
+	imp := imports[pkg]
+	if imp.Name != "" && imp.Path != "" {
+		g.importAliases[imp.Path] = imp.Name
+	}
+	return namedRef(imp.Path, name), nil
+}
+
+// resolveImports maps the package qualifiers in node to imports. A qualifier matches the import
+// with that name, or with that last path element when the import has no name. When there is a
+// single import and the expression has a single qualifier, the qualifier refers to that import
+// whatever its name. It returns nil when there are no imports.
+func resolveImports(node ast.Expr, imports []schema.GoTypeImport) (map[string]schema.GoTypeImport, error) {
+	if len(imports) == 0 {
+		return nil, nil
+	}
+	var qualifiers []string
+	ast.Inspect(node, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if pkg, ok := sel.X.(*ast.Ident); ok && !slices.Contains(qualifiers, pkg.Name) {
+				qualifiers = append(qualifiers, pkg.Name)
+			}
+		}
+		return true
+	})
+	resolved := make(map[string]schema.GoTypeImport, len(qualifiers))
+	for _, pkg := range qualifiers {
+		imp, ok := matchImport(pkg, imports)
+		if !ok && len(imports) == 1 && len(qualifiers) == 1 {
+			imp, ok = imports[0], true
+		}
+		if !ok {
+			return nil, fmt.Errorf("no x-go-type-import for package %s", pkg)
+		}
+		resolved[pkg] = imp
+	}
+	return resolved, nil
+}
+
+func matchImport(pkg string, imports []schema.GoTypeImport) (schema.GoTypeImport, bool) {
+	for _, imp := range imports {
+		importName := imp.Name
+		if importName == "" {
+			importName = path.Base(imp.Path)
+		}
+		if importName == pkg {
+			return imp, true
+		}
+	}
+	return schema.GoTypeImport{}, false
+}
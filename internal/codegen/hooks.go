@@ -0,0 +1,67 @@
+package codegen
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// Hook customizes the rendered code. Hooks are called in order for every type declaration and
+// struct field when a Model is rendered. Embed BaseHook to only implement one of the methods.
+type Hook interface {
+	// Type is called for every type declaration after its fields are rendered.
+	Type(ctx *TypeHookContext) error
+	// Field is called for every struct field.
+	Field(ctx *FieldHookContext) error
+}
+
+// BaseHook is a Hook that does nothing.
+type BaseHook struct{}
+
+func (BaseHook) Type(*TypeHookContext) error { return nil }
+
+func (BaseHook) Field(*FieldHookContext) error { return nil }
+
+// TypeHookContext is the type declaration a Hook is called for.
+type TypeHookContext struct {
+	Decl *TypeDecl
+	// Schema is the schema the type is generated from. It is nil for models that weren't built by
+	// BuildModel.
+	Schema     *schema.Schema
+	Extensions *schema.Extensions
+	// Statement is the declaration. Hooks may modify it.
+	Statement *jen.Statement
+	// After is code that is added after the declaration, such as methods.
+	After []jen.Code
+}
+
+// FieldHookContext is the struct field a Hook is called for.
+type FieldHookContext struct {
+	// Decl is the struct the field belongs to.
+	Decl  *TypeDecl
+	Field *Field
+	// Schema is the property's schema. It is nil for models that weren't built by BuildModel.
+	Schema     *schema.Schema
+	Extensions *schema.Extensions
+	// Statement is the field's name and type. Hooks may modify it.
+	Statement *jen.Statement
+	// Tags are the field's struct tags. They start with the json tag.
+	Tags map[string]string
+}
+
+// builtinHooks run before the hooks in Options.Hooks.
+var builtinHooks = []Hook{goTagsHook{}}
+
+// goTagsHook adds the struct tags in a property's x-go-tags extension.
+type goTagsHook struct {
+	BaseHook
+}
+
+func (goTagsHook) Field(ctx *FieldHookContext) error {
+	if ctx.Extensions == nil {
+		return nil
+	}
+	for key, value := range ctx.Extensions.GoTags {
+		ctx.Tags[key] = value
+	}
+	return nil
+}
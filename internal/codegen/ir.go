@@ -0,0 +1,342 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// Model is the intermediate representation of generated code. It is built from schemas by
+// BuildModel and rendered to Go source by RenderModel, and it marshals to JSON for inspecting why a
+// type came out the way it did.
+type Model struct {
+	Files []*File `json:"files"`
+	// ImportAliases are package names to use for imports keyed by import path.
+	ImportAliases map[string]string `json:"importAliases,omitempty"`
+}
+
+// File is a generated Go file.
+type File struct {
+	// Name is the path of the file relative to the output directory.
+	Name        string      `json:"name"`
+	PackageName string      `json:"packageName"`
+	PackagePath string      `json:"packagePath,omitempty"`
+	Types       []*TypeDecl `json:"types"`
+	// Helpers are generic helper types declared in the file, such as Optional.
+	Helpers []string `json:"helpers,omitempty"`
+}
+
+// TypeDeclKind is the kind of a type declaration.
+type TypeDeclKind string
+
+const (
+	// TypeDeclStruct declares a struct with Fields.
+	TypeDeclStruct TypeDeclKind = "struct"
+	// TypeDeclDefined declares a type with Type as its underlying type.
+	TypeDeclDefined TypeDeclKind = "defined"
+)
+
+// TypeDecl is a type declaration.
+type TypeDecl struct {
+	Name string       `json:"name"`
+	Kind TypeDeclKind `json:"kind"`
+	// Origin is the location of the schema the type is generated from.
+	Origin string `json:"origin"`
+	// Doc is the schema's description.
+	Doc string `json:"doc,omitempty"`
+	// Extensions are the schema's keywords that start with "x-".
+	Extensions map[string]any `json:"extensions,omitempty"`
+	Fields     []*Field       `json:"fields,omitempty"`
+	Type       *TypeRef       `json:"type,omitempty"`
+	// Getters generates a getter method for every field.
+	Getters bool `json:"getters,omitempty"`
+	// Builders generates a constructor that takes the required fields and a setter for every other
+	// field.
+	Builders bool `json:"builders,omitempty"`
+
+	schema *schema.Schema
+}
+
+// Field is a struct field generated from an object property.
+type Field struct {
+	Name string `json:"name"`
+	// JSONName is the property name.
+	JSONName string   `json:"jsonName"`
+	Type     *TypeRef `json:"type"`
+	Required bool     `json:"required"`
+	// OmitZero adds the omitzero option to the json tag.
+	OmitZero bool `json:"omitZero,omitempty"`
+	// Origin is the location of the property's schema.
+	Origin string `json:"origin"`
+	// Doc is the property's description.
+	Doc string `json:"doc,omitempty"`
+	// Extensions are the property schema's keywords that start with "x-".
+	Extensions map[string]any `json:"extensions,omitempty"`
+	// Default is the property's default value.
+	Default any `json:"default,omitempty"`
+
+	schema *schema.Schema
+}
+
+// TypeRefKind is the kind of a Go type expression.
+type TypeRefKind string
+
+const (
+	// TypeRefNamed is a named type such as string, Person or time.Time.
+	TypeRefNamed TypeRefKind = "named"
+	// TypeRefPointer is a pointer to Elem.
+	TypeRefPointer TypeRefKind = "pointer"
+	// TypeRefSlice is a slice of Elem.
+	TypeRefSlice TypeRefKind = "slice"
+	// TypeRefArray is an array of Len Elem.
+	TypeRefArray TypeRefKind = "array"
+	// TypeRefMap is a map from Key to Elem.
+	TypeRefMap TypeRefKind = "map"
+	// TypeRefInterface is the empty interface.
+	TypeRefInterface TypeRefKind = "interface"
+)
+
+// TypeRef is a Go type expression.
+type TypeRef struct {
+	Kind TypeRefKind `json:"kind"`
+	// Name is the name of a named type.
+	Name string `json:"name,omitempty"`
+	// Package is the import path of the package that declares a named type. It is empty for
+	// predeclared types and types in the file's own package when the package has no import path.
+	Package string `json:"package,omitempty"`
+	// TypeArgs are the type arguments of a generic named type.
+	TypeArgs []*TypeRef `json:"typeArgs,omitempty"`
+	// Key is the key type of a map. It is string when Key is nil.
+	Key  *TypeRef `json:"key,omitempty"`
+	Elem *TypeRef `json:"elem,omitempty"`
+	// Len is the length of an array.
+	Len int `json:"len,omitempty"`
+}
+
+// String returns the type expression as Go code. Types from other packages are qualified with the
+// last element of their import path.
+func (t *TypeRef) String() string {
+	switch t.Kind {
+	case TypeRefPointer:
+		return "*" + t.Elem.String()
+	case TypeRefSlice:
+		return "[]" + t.Elem.String()
+	case TypeRefArray:
+		return "[" + strconv.Itoa(t.Len) + "]" + t.Elem.String()
+	case TypeRefMap:
+		key := "string"
+		if t.Key != nil {
+			key = t.Key.String()
+		}
+		return "map[" + key + "]" + t.Elem.String()
+	case TypeRefInterface:
+		return "interface{}"
+	default:
+		name := t.Name
+		if t.Package != "" {
+			name = path.Base(t.Package) + "." + name
+		}
+		if len(t.TypeArgs) == 0 {
+			return name
+		}
+		args := make([]string, len(t.TypeArgs))
+		for i, arg := range t.TypeArgs {
+			args[i] = arg.String()
+		}
+		return name + "[" + strings.Join(args, ", ") + "]"
+	}
+}
+
+// Types returns the type declarations of every file.
+func (m *Model) Types() []*TypeDecl {
+	var types []*TypeDecl
+	for _, f := range m.Files {
+		types = append(types, f.Types...)
+	}
+	return types
+}
+
+func namedRef(pkgPath, name string) *TypeRef {
+	return &TypeRef{Kind: TypeRefNamed, Name: name, Package: pkgPath}
+}
+
+func pointerTo(elem *TypeRef) *TypeRef {
+	return &TypeRef{Kind: TypeRefPointer, Elem: elem}
+}
+
+func sliceOf(elem *TypeRef) *TypeRef {
+	return &TypeRef{Kind: TypeRefSlice, Elem: elem}
+}
+
+func mapOf(elem *TypeRef) *TypeRef {
+	return &TypeRef{Kind: TypeRefMap, Elem: elem}
+}
+
+func interfaceRef() *TypeRef {
+	return &TypeRef{Kind: TypeRefInterface}
+}
+
+// code returns the jen code for the type expression.
+func (t *TypeRef) code() jen.Code {
+	switch t.Kind {
+	case TypeRefPointer:
+		return jen.Op("*").Add(t.Elem.code())
+	case TypeRefSlice:
+		return jen.Index().Add(t.Elem.code())
+	case TypeRefArray:
+		return jen.Index(jen.Lit(t.Len)).Add(t.Elem.code())
+	case TypeRefMap:
+		if t.Key != nil {
+			return jen.Map(t.Key.code()).Add(t.Elem.code())
+		}
+		return jen.Map(jen.String()).Add(t.Elem.code())
+	case TypeRefInterface:
+		return jen.Interface()
+	default:
+		stmt := jen.Qual(t.Package, t.Name)
+		if t.Package == "" {
+			stmt = jen.Id(t.Name)
+		}
+		if len(t.TypeArgs) > 0 {
+			args := make([]jen.Code, len(t.TypeArgs))
+			for i, arg := range t.TypeArgs {
+				args[i] = arg.code()
+			}
+			stmt.Types(args...)
+		}
+		return stmt
+	}
+}
+
+// code returns the jen code for the type declaration followed by its constructor, setters, getters
+// and the code that hooks add after it.
+func (d *TypeDecl) code(types typeIndex, hooks []Hook) ([]jen.Code, error) {
+	ctx := &TypeHookContext{
+		Decl:   d,
+		Schema: d.schema,
+	}
+	if d.Kind == TypeDeclStruct {
+		fields := make([]jen.Code, 0, len(d.Fields))
+		for _, field := range d.Fields {
+			fieldCode, err := field.code(d, hooks)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, fieldCode)
+		}
+		ctx.Statement = jen.Type().Id(d.Name).Struct(fields...)
+		if d.Builders {
+			ctx.After = append(ctx.After, d.builderCode()...)
+		}
+		if d.Getters {
+			ctx.After = append(ctx.After, d.getterCode(types)...)
+		}
+	} else {
+		ctx.Statement = jen.Type().Id(d.Name).Add(d.Type.code())
+	}
+	if len(hooks) == 0 {
+		return append([]jen.Code{ctx.Statement}, ctx.After...), nil
+	}
+	var err error
+	ctx.Extensions, err = extensions(d.schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, hook := range hooks {
+		err = hook.Type(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append([]jen.Code{ctx.Statement}, ctx.After...), nil
+}
+
+// code returns the jen code for a field of decl.
+func (f *Field) code(decl *TypeDecl, hooks []Hook) (jen.Code, error) {
+	ctx := &FieldHookContext{
+		Decl:      decl,
+		Field:     f,
+		Schema:    f.schema,
+		Statement: jen.Id(f.Name).Add(f.Type.code()),
+		Tags:      map[string]string{"json": f.jsonTag()},
+	}
+	if len(hooks) > 0 {
+		var err error
+		ctx.Extensions, err = extensions(f.schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, hook := range hooks {
+		err := hook.Field(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ctx.Statement.Tag(ctx.Tags), nil
+}
+
+func (f *Field) jsonTag() string {
+	if f.OmitZero {
+		return f.JSONName + ",omitzero"
+	}
+	return f.JSONName
+}
+
+// extensions returns the extensions of sch, which may be nil.
+func extensions(sch *schema.Schema) (*schema.Extensions, error) {
+	if sch == nil {
+		return &schema.Extensions{}, nil
+	}
+	return sch.Extensions()
+}
+
+// RenderModel renders every file in the model keyed by its path relative to the output directory.
+// The built-in hooks run before hooks.
+func RenderModel(model *Model, hooks []Hook) (map[string][]byte, error) {
+	hooks = append(slices.Clone(builtinHooks), hooks...)
+	files := make(map[string][]byte, len(model.Files))
+	for _, f := range model.Files {
+		var buf bytes.Buffer
+		err := renderModelFile(&buf, model, f, hooks)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", f.Name, err)
+		}
+		files[f.Name] = buf.Bytes()
+	}
+	return files, nil
+}
+
+// renderModelFile renders a single file of the model.
+func renderModelFile(w io.Writer, model *Model, f *File, hooks []Hook) error {
+	file := jen.NewFilePathName(f.PackagePath, f.PackageName)
+	file.HeaderComment("Code generated by jsonschematogo. DO NOT EDIT.")
+	for importPath, alias := range model.ImportAliases {
+		file.ImportAlias(importPath, alias)
+	}
+	types := newTypeIndex(model)
+	for _, decl := range f.Types {
+		code, err := decl.code(types, hooks)
+		if err != nil {
+			return fmt.Errorf("type %s: %w", decl.Name, err)
+		}
+		for _, c := range code {
+			file.Add(c)
+			file.Line()
+		}
+	}
+	for _, helper := range f.Helpers {
+		for _, c := range helperCode(helper) {
+			file.Add(c)
+		}
+		file.Line()
+	}
+	return file.Render(w)
+}
@@ -0,0 +1,89 @@
+package codegen_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/codegen"
+	"github.com/willabides/jsonschematogo/internal/schema"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+func TestBuildModel(t *testing.T) {
+	const location = "mem://schemas/person.json"
+	sch, err := schema.LoadSchema(location, &schemaloader.Options{
+		Sources: map[string][]byte{
+			location: []byte(`{
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"pet": {"$ref": "#/$defs/pet"}
+				},
+				"$defs": {"pet": {"type": "string"}}
+			}`),
+		},
+	})
+	require.NoError(t, err)
+	model, err := codegen.BuildModel([]*schema.Schema{sch}, &codegen.Options{PackageName: "people"})
+	require.NoError(t, err)
+	// Compare JSON because the model also references the schemas.
+	want, err := json.Marshal(&codegen.Model{
+		ImportAliases: map[string]string{},
+		Files: []*codegen.File{{
+			Name:        "people.go",
+			PackageName: "people",
+			Types: []*codegen.TypeDecl{
+				{
+					Name:   "Pet",
+					Kind:   codegen.TypeDeclDefined,
+					Origin: location + "#/$defs/pet",
+					Type:   &codegen.TypeRef{Kind: codegen.TypeRefNamed, Name: "string"},
+				},
+				{
+					Name:   "Person",
+					Kind:   codegen.TypeDeclStruct,
+					Origin: location + "#",
+					Fields: []*codegen.Field{
+						{
+							Name:     "Name",
+							JSONName: "name",
+							Type:     &codegen.TypeRef{Kind: codegen.TypeRefNamed, Name: "string"},
+							Required: true,
+							Origin:   location + "#/properties/name",
+						},
+						{
+							Name:     "Pet",
+							JSONName: "pet",
+							Type: &codegen.TypeRef{
+								Kind: codegen.TypeRefPointer,
+								Elem: &codegen.TypeRef{Kind: codegen.TypeRefNamed, Name: "Pet"},
+							},
+							Origin: location + "#/properties/pet",
+						},
+						{
+							Name:     "Tags",
+							JSONName: "tags",
+							Type: &codegen.TypeRef{
+								Kind: codegen.TypeRefSlice,
+								Elem: &codegen.TypeRef{Kind: codegen.TypeRefNamed, Name: "string"},
+							},
+							Origin: location + "#/properties/tags",
+						},
+					},
+				},
+			},
+		}},
+	})
+	require.NoError(t, err)
+	got, err := json.Marshal(model)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+
+	files, err := codegen.RenderModel(model, nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(files["people.go"]), "type Person struct {\n\tName string   `json:\"name\"`\n")
+}
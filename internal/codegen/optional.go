@@ -0,0 +1,184 @@
+package codegen
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// OptionalStrategy is how fields for properties that aren't required are represented.
+type OptionalStrategy string
+
+const (
+	// OptionalPointer uses a pointer to the type. Slices, maps and pointers are used as they are
+	// because they can already be nil.
+	OptionalPointer OptionalStrategy = "pointer"
+	// OptionalOmitZero uses the type itself with the omitzero option in the json tag. It needs Go
+	// 1.24.
+	OptionalOmitZero OptionalStrategy = "omitzero"
+	// OptionalGeneric uses a generated Optional[T] that records whether the property was present.
+	// Absent properties are only omitted when marshaling with Go 1.24 or later.
+	OptionalGeneric OptionalStrategy = "optional"
+	// OptionalNullable uses a generated Nullable[T] that records whether the property was present
+	// and whether it was null. Absent properties are only omitted when marshaling with Go 1.24 or
+	// later.
+	OptionalNullable OptionalStrategy = "nullable"
+)
+
+// OptionalStrategies are the valid values of OptionalStrategy.
+var OptionalStrategies = []OptionalStrategy{OptionalPointer, OptionalOmitZero, OptionalGeneric, OptionalNullable}
+
+// optionalTypeExpr returns the type expression for a property of parent that isn't required and
+// whether its json tag needs omitzero. typeExpr is the type the property would have if it were
+// required.
+func (g *generator) optionalTypeExpr(prop, parent *schema.Schema, typeExpr *TypeRef) (*TypeRef, bool, error) {
+	ext, err := prop.Extensions()
+	if err != nil {
+		return nil, false, err
+	}
+	if ext.GoTypeSkipOptionalPointer != nil && *ext.GoTypeSkipOptionalPointer {
+		return typeExpr, false, nil
+	}
+	strategy := g.opts.Optional
+	if strategy == "" {
+		strategy = OptionalPointer
+	}
+	if ext.GoOptional != nil {
+		strategy = OptionalStrategy(*ext.GoOptional)
+		if !slices.Contains(OptionalStrategies, strategy) {
+			return nil, false, prop.KeywordError("x-go-optional", fmt.Errorf(
+				"invalid x-go-optional: %q is not one of %v", *ext.GoOptional, OptionalStrategies,
+			))
+		}
+	}
+	switch strategy {
+	case OptionalOmitZero:
+		return typeExpr, true, nil
+	case OptionalGeneric:
+		return g.helperRef(parent.Location(), helperOptional, typeExpr), true, nil
+	case OptionalNullable:
+		return g.helperRef(parent.Location(), helperNullable, typeExpr), true, nil
+	default:
+		if typeExpr.Kind == TypeRefSlice || typeExpr.Kind == TypeRefMap || typeExpr.Kind == TypeRefPointer {
+			return typeExpr, false, nil
+		}
+		return pointerTo(typeExpr), false, nil
+	}
+}
+
+// Helpers that are generated into packages that use them.
+const (
+	helperOptional = "Optional"
+	helperNullable = "Nullable"
+	helperPtr      = "Ptr"
+)
+
+// helperRef returns a reference to a helper type instantiated with typeArg. The helper is declared
+// in the package that owns location the first time it is used there.
+func (g *generator) helperRef(location, helper string, typeArg *TypeRef) *TypeRef {
+	f := g.addHelper(location, helper)
+	ref := namedRef(f.pkg.Path, helper)
+	ref.TypeArgs = []*TypeRef{typeArg}
+	return ref
+}
+
+// addHelper declares a helper in the package that owns location unless it is already declared
+// there. It returns the file that owns location.
+func (g *generator) addHelper(location, helper string) *outputFile {
+	f := g.fileFor(location)
+	for _, other := range g.fileOrder {
+		if other.pkg.Path == f.pkg.Path && other.pkg.Dir == f.pkg.Dir && slices.Contains(other.file.Helpers, helper) {
+			return f
+		}
+	}
+	f.file.Helpers = append(f.file.Helpers, helper)
+	return f
+}
+
+// helperCode returns the declaration of a helper and its methods.
+func helperCode(helper string) []jen.Code {
+	switch helper {
+	case helperOptional:
+		return optionalCode()
+	case helperNullable:
+		return nullableCode()
+	case helperPtr:
+		return ptrCode()
+	}
+	return nil
+}
+
+func optionalCode() []jen.Code {
+	recv := func(pointer bool) *jen.Statement {
+		if pointer {
+			return jen.Id("o").Op("*").Id(helperOptional).Types(jen.Id("T"))
+		}
+		return jen.Id("o").Id(helperOptional).Types(jen.Id("T"))
+	}
+	return []jen.Code{
+		jen.Comment("Optional is a value for a property that may be absent. Fields of this type have the omitzero"),
+		jen.Comment("json option, so with Go 1.24 or later they are omitted from JSON when Set is false."),
+		jen.Type().Id(helperOptional).Types(jen.Id("T").Any()).Struct(
+			jen.Id("Value").Id("T"),
+			jen.Comment("Set reports whether the property is present."),
+			jen.Id("Set").Bool(),
+		),
+		jen.Line(),
+		jen.Comment("IsZero reports whether the property is absent."),
+		jen.Func().Params(recv(false)).Id("IsZero").Params().Bool().Block(
+			jen.Return(jen.Op("!").Id("o").Dot("Set")),
+		),
+		jen.Line(),
+		jen.Func().Params(recv(false)).Id("MarshalJSON").Params().Params(jen.Index().Byte(), jen.Error()).Block(
+			jen.Return(jen.Qual("encoding/json", "Marshal").Call(jen.Id("o").Dot("Value"))),
+		),
+		jen.Line(),
+		jen.Func().Params(recv(true)).Id("UnmarshalJSON").Params(jen.Id("data").Index().Byte()).Error().Block(
+			jen.Id("o").Dot("Set").Op("=").True(),
+			jen.Return(jen.Qual("encoding/json", "Unmarshal").Call(jen.Id("data"), jen.Op("&").Id("o").Dot("Value"))),
+		),
+	}
+}
+
+func nullableCode() []jen.Code {
+	recv := func(pointer bool) *jen.Statement {
+		if pointer {
+			return jen.Id("n").Op("*").Id(helperNullable).Types(jen.Id("T"))
+		}
+		return jen.Id("n").Id(helperNullable).Types(jen.Id("T"))
+	}
+	return []jen.Code{
+		jen.Comment("Nullable is a value for a property that may be absent or null. Fields of this type have the"),
+		jen.Comment("omitzero json option, so with Go 1.24 or later they are omitted from JSON when Set is false."),
+		jen.Type().Id(helperNullable).Types(jen.Id("T").Any()).Struct(
+			jen.Id("Value").Id("T"),
+			jen.Comment("Set reports whether the property is present."),
+			jen.Id("Set").Bool(),
+			jen.Comment("Null reports whether the property is null."),
+			jen.Id("Null").Bool(),
+		),
+		jen.Line(),
+		jen.Comment("IsZero reports whether the property is absent."),
+		jen.Func().Params(recv(false)).Id("IsZero").Params().Bool().Block(
+			jen.Return(jen.Op("!").Id("n").Dot("Set")),
+		),
+		jen.Line(),
+		jen.Func().Params(recv(false)).Id("MarshalJSON").Params().Params(jen.Index().Byte(), jen.Error()).Block(
+			jen.If(jen.Id("n").Dot("Null")).Block(
+				jen.Return(jen.Index().Byte().Call(jen.Lit("null")), jen.Nil()),
+			),
+			jen.Return(jen.Qual("encoding/json", "Marshal").Call(jen.Id("n").Dot("Value"))),
+		),
+		jen.Line(),
+		jen.Func().Params(recv(true)).Id("UnmarshalJSON").Params(jen.Id("data").Index().Byte()).Error().Block(
+			jen.Op("*").Id("n").Op("=").Id(helperNullable).Types(jen.Id("T")).Values(jen.Dict{jen.Id("Set"): jen.True()}),
+			jen.If(jen.String().Call(jen.Id("data")).Op("==").Lit("null")).Block(
+				jen.Id("n").Dot("Null").Op("=").True(),
+				jen.Return(jen.Nil()),
+			),
+			jen.Return(jen.Qual("encoding/json", "Unmarshal").Call(jen.Id("data"), jen.Op("&").Id("n").Dot("Value"))),
+		),
+	}
+}
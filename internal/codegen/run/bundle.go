@@ -0,0 +1,75 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/willabides/jsonschematogo/internal/schemabundle"
+	"gopkg.in/yaml.v3"
+)
+
+// BundleCmd writes a schema with every document it references inlined so that it can be used
+// without access to the referenced URLs.
+type BundleCmd struct {
+	File          string `kong:"arg,help='Entry schema file or URL'"`
+	Output        string `kong:"short=o,help='Output file path (defaults to stdout)'"`
+	Format        string `kong:"enum='auto,json,yaml',default='auto',help='Output format. auto uses yaml for .yaml and .yml output files and json otherwise'"`
+	LoaderOptions `kong:"embed"`
+	Lockfile      string `kong:"placeholder='FILE',group=parsing,help='Lockfile written by the vendor command. Remote schemas are loaded from the vendor directory and verified against their checksums (defaults to vendored-schemas/jsonschematogo.lock when it exists)'"`
+}
+
+func (cli *BundleCmd) Run(k *kong.Context) error {
+	opts, err := cli.loaderOptions(lockfile(cli.Lockfile))
+	if err != nil {
+		return err
+	}
+	bundled, err := schemabundle.Bundle(cli.File, opts)
+	if err != nil {
+		return err
+	}
+	content, err := encodeBundle(bundled, cli.format())
+	if err != nil {
+		return err
+	}
+	return writeFiles(k.Stdout, map[string][]byte{cli.Output: content})
+}
+
+// format returns the output format, resolving auto from the output file's extension.
+func (cli *BundleCmd) format() string {
+	if cli.Format != "auto" {
+		return cli.Format
+	}
+	switch strings.ToLower(filepath.Ext(cli.Output)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
+
+func encodeBundle(bundled any, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	if format == "yaml" {
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		err := encoder.Encode(bundled)
+		if err == nil {
+			err = encoder.Close()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("encoding bundle: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(bundled)
+	if err != nil {
+		return nil, fmt.Errorf("encoding bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
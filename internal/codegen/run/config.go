@@ -0,0 +1,259 @@
+package run
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/alecthomas/kong"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is the config file that is used when --config isn't set.
+const defaultConfigFile = "jsonschematogo.yaml"
+
+// configSchemaURL is the $id of config.schema.json.
+const configSchemaURL = "https://github.com/willabides/jsonschematogo/config.schema.json"
+
+//go:embed config.schema.json
+var configSchemaJSON []byte
+
+// Config is the content of a config file. The top level options are the defaults for every job.
+// When there are no jobs, the top level options are a job of their own.
+type Config struct {
+	GenerateOptions `yaml:",inline"`
+	Jobs            []GenerateOptions `yaml:"jobs"`
+
+	// keys and jobKeys are the options that are set at the top level and in each job.
+	keys    optionSet
+	jobKeys []optionSet
+}
+
+// optionSet holds the names of options that were explicitly set.
+type optionSet map[string]bool
+
+// loadConfig reads, validates and decodes a config file. Relative paths in the config are resolved
+// relative to the config file's directory.
+func loadConfig(filename string) (*Config, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	err = validateConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", filename, err)
+	}
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&cfg)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("decoding config %s: %w", filename, err)
+	}
+	cfg.keys, cfg.jobKeys, err = configKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding config %s: %w", filename, err)
+	}
+	dir := filepath.Dir(filename)
+	cfg.resolvePaths(dir)
+	for i := range cfg.Jobs {
+		cfg.Jobs[i].resolvePaths(dir)
+	}
+	return &cfg, nil
+}
+
+// configKeys returns the options that are set at the top level of a config file and in each of its
+// jobs.
+func configKeys(data []byte) (optionSet, []optionSet, error) {
+	var doc struct {
+		Options map[string]any   `yaml:",inline"`
+		Jobs    []map[string]any `yaml:"jobs"`
+	}
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	keys := make(optionSet, len(doc.Options))
+	for key := range doc.Options {
+		keys[key] = true
+	}
+	jobKeys := make([]optionSet, len(doc.Jobs))
+	for i, job := range doc.Jobs {
+		jobKeys[i] = make(optionSet, len(job))
+		for key := range job {
+			jobKeys[i][key] = true
+		}
+	}
+	return keys, jobKeys, nil
+}
+
+func validateConfig(data []byte) error {
+	var doc any
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	schemaDoc, err := jsonschema.UnmarshalJSON(bytes.NewReader(configSchemaJSON))
+	if err != nil {
+		return err
+	}
+	compiler := jsonschema.NewCompiler()
+	err = compiler.AddResource(configSchemaURL, schemaDoc)
+	if err != nil {
+		return err
+	}
+	configSchema, err := compiler.Compile(configSchemaURL)
+	if err != nil {
+		return err
+	}
+	return configSchema.Validate(doc)
+}
+
+// resolvePaths makes relative file paths relative to dir.
+func (o *GenerateOptions) resolvePaths(dir string) {
+	for _, paths := range [][]string{o.Files, o.Include, o.Exclude, o.Template} {
+		for i, p := range paths {
+			paths[i] = resolvePath(dir, p)
+		}
+	}
+	o.Output = resolvePath(dir, o.Output)
+	o.OutputDir = resolvePath(dir, o.OutputDir)
+	o.BaseDir = resolvePath(dir, o.BaseDir)
+	o.CACert = resolvePath(dir, o.CACert)
+	o.CacheDir = resolvePath(dir, o.CacheDir)
+	o.StdinURI = resolvePath(dir, o.StdinURI)
+	o.Lockfile = resolvePath(dir, o.Lockfile)
+	for prefix, target := range o.URLMap {
+		o.URLMap[prefix] = resolvePath(dir, target)
+	}
+	o.PackageMap = resolveKeyPaths(dir, o.PackageMap)
+	o.TypeMap = resolveKeyPaths(dir, o.TypeMap)
+}
+
+func resolveKeyPaths(dir string, mp map[string]string) map[string]string {
+	if mp == nil {
+		return nil
+	}
+	resolved := make(map[string]string, len(mp))
+	for key, value := range mp {
+		resolved[resolvePath(dir, key)] = value
+	}
+	return resolved
+}
+
+// resolvePath joins relative local paths to dir. Absolute paths and URLs are returned unchanged.
+// A trailing slash is preserved because it is significant for URL prefixes.
+func resolvePath(dir, p string) string {
+	if p == "" || filepath.IsAbs(p) || strings.Contains(p, "://") {
+		return p
+	}
+	resolved := filepath.Join(dir, p)
+	if strings.HasSuffix(p, "/") {
+		resolved += "/"
+	}
+	return resolved
+}
+
+// configFile returns the config file to use or an empty string when there is none.
+func (cli *GenerateCmd) configFile() string {
+	return configFile(cli.Config)
+}
+
+// configFile returns name, or the default config file when name is empty and the default exists.
+func configFile(name string) string {
+	if name != "" {
+		return name
+	}
+	_, err := os.Stat(defaultConfigFile)
+	if err != nil {
+		return ""
+	}
+	return defaultConfigFile
+}
+
+// jobs returns the generation jobs to run.
+func (cli *GenerateCmd) jobs(k *kong.Context) ([]GenerateOptions, error) {
+	return loadJobs(k, cli.configFile(), &cli.GenerateOptions)
+}
+
+// loadJobs returns the jobs from configFile. Options from the config file are overridden by flags
+// that were explicitly set on the command line. When files are given on the command line, the jobs
+// in the config file are ignored.
+func loadJobs(k *kong.Context, configFile string, opts *GenerateOptions) ([]GenerateOptions, error) {
+	if configFile == "" {
+		return []GenerateOptions{*opts}, nil
+	}
+	cfg, err := loadConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	flags := explicitFlags(k)
+	if len(opts.Files) > 0 {
+		// Files is a positional argument, so it isn't among the flags.
+		flags["files"] = true
+	}
+	base := mergeOptions(*opts, cfg.GenerateOptions, cfg.keys)
+	if len(opts.Files) > 0 || len(cfg.Jobs) == 0 {
+		return []GenerateOptions{mergeOptions(base, *opts, flags)}, nil
+	}
+	jobs := make([]GenerateOptions, 0, len(cfg.Jobs))
+	for i, job := range cfg.Jobs {
+		jobs = append(jobs, mergeOptions(mergeOptions(base, job, cfg.jobKeys[i]), *opts, flags))
+	}
+	return jobs, nil
+}
+
+// explicitFlags returns the names of the flags that were set on the command line.
+func explicitFlags(k *kong.Context) optionSet {
+	explicit := optionSet{}
+	for _, p := range k.Path {
+		if p.Flag != nil && !p.Resolved {
+			explicit[p.Flag.Name] = true
+		}
+	}
+	return explicit
+}
+
+// mergeOptions returns base with the fields of override that are named in set applied, even when
+// they are zero. Maps are merged key by key.
+func mergeOptions(base, override GenerateOptions, set optionSet) GenerateOptions {
+	result := base
+	mergeFields(reflect.ValueOf(&result).Elem(), reflect.ValueOf(override), set)
+	return result
+}
+
+// mergeFields applies the fields of src that are named in set to dst. Inlined structs are merged
+// field by field.
+func mergeFields(dst, src reflect.Value, set optionSet) {
+	for i := range src.NumField() {
+		name, opts, _ := strings.Cut(src.Type().Field(i).Tag.Get("yaml"), ",")
+		field := src.Field(i)
+		switch {
+		case opts == "inline":
+			mergeFields(dst.Field(i), field, set)
+		case !set[name]:
+		case field.Kind() != reflect.Map || dst.Field(i).IsNil() || field.IsNil():
+			dst.Field(i).Set(field)
+		default:
+			merged := reflect.MakeMap(field.Type())
+			for _, mp := range []reflect.Value{dst.Field(i), field} {
+				iter := mp.MapRange()
+				for iter.Next() {
+					merged.SetMapIndex(iter.Key(), iter.Value())
+				}
+			}
+			dst.Field(i).Set(merged)
+		}
+	}
+}
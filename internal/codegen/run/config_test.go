@@ -0,0 +1,148 @@
+package run_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/codegen/run"
+	"github.com/willabides/jsonschematogo/internal/codegen/run/testrun"
+)
+
+func TestConfig(t *testing.T) {
+	schemasDir, err := filepath.Abs("../testdata/schemas")
+	require.NoError(t, err)
+
+	writeConfig := func(t *testing.T, content string) (dir, configFile string) {
+		t.Helper()
+		dir = t.TempDir()
+		configFile = filepath.Join(dir, "jsonschematogo.yaml")
+		content = strings.ReplaceAll(content, "SCHEMAS", filepath.ToSlash(schemasDir))
+		require.NoError(t, os.WriteFile(configFile, []byte(content), 0o600))
+		return dir, configFile
+	}
+
+	t.Run("jobs", func(t *testing.T) {
+		dir, configFile := writeConfig(t, `
+package: models
+jobs:
+  - files: [SCHEMAS/primitives.yaml]
+    output: primitives.go
+  - files: [SCHEMAS/company/company.yaml]
+    output: company.go
+    package: company
+`)
+		result := testrun.Run("--config", configFile)
+		result.AssertSuccess(t)
+		primitives, err := os.ReadFile(filepath.Join(dir, "primitives.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(primitives), "package models\n")
+		company, err := os.ReadFile(filepath.Join(dir, "company.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(company), "package company\n")
+		assert.Contains(t, string(company), "type Company struct")
+	})
+
+	t.Run("flags override config", func(t *testing.T) {
+		dir, configFile := writeConfig(t, `
+package: models
+files: [SCHEMAS/primitives.yaml]
+output: primitives.go
+`)
+		result := testrun.Run("--config", configFile, "--package", "override")
+		result.AssertSuccess(t)
+		primitives, err := os.ReadFile(filepath.Join(dir, "primitives.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(primitives), "package override\n")
+	})
+
+	t.Run("files on the command line override config files", func(t *testing.T) {
+		_, configFile := writeConfig(t, `
+files: [SCHEMAS/primitives.yaml]
+`)
+		result := testrun.Run("--config", configFile, filepath.Join(schemasDir, "company", "company.yaml"))
+		result.AssertSuccess(t)
+		assert.Contains(t, result.Stdout, "type Company struct")
+		assert.NotContains(t, result.Stdout, "type Primitives struct")
+	})
+
+	t.Run("false overrides true", func(t *testing.T) {
+		dir, configFile := writeConfig(t, `
+getters: true
+jobs:
+  - files: [SCHEMAS/company/company.yaml]
+    output: job.go
+    getters: false
+`)
+		result := testrun.Run("--config", configFile)
+		result.AssertSuccess(t)
+		job, err := os.ReadFile(filepath.Join(dir, "job.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(job), "type Company struct")
+		assert.NotContains(t, string(job), ") Get")
+
+		dir, configFile = writeConfig(t, `
+getters: true
+files: [SCHEMAS/company/company.yaml]
+output: flag.go
+`)
+		result = testrun.Run("--config", configFile, "--getters=false")
+		result.AssertSuccess(t)
+		flag, err := os.ReadFile(filepath.Join(dir, "flag.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(flag), "type Company struct")
+		assert.NotContains(t, string(flag), ") Get")
+
+		result = testrun.Run("--config", configFile)
+		result.AssertSuccess(t)
+		flag, err = os.ReadFile(filepath.Join(dir, "flag.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(flag), ") Get")
+	})
+
+	t.Run("unknown option", func(t *testing.T) {
+		_, configFile := writeConfig(t, `
+packages: models
+`)
+		result := testrun.Run("--config", configFile)
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "invalid config")
+	})
+}
+
+// TestConfigSchema checks that config.schema.json covers every option.
+func TestConfigSchema(t *testing.T) {
+	data, err := os.ReadFile("config.schema.json")
+	require.NoError(t, err)
+	var configSchema struct {
+		Defs struct {
+			Options struct {
+				Properties map[string]any `json:"properties"`
+			} `json:"options"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &configSchema))
+	var want []string
+	var optionNames func(reflect.Type)
+	optionNames = func(optionsType reflect.Type) {
+		for i := range optionsType.NumField() {
+			name, opts, _ := strings.Cut(optionsType.Field(i).Tag.Get("yaml"), ",")
+			if opts == "inline" {
+				optionNames(optionsType.Field(i).Type)
+				continue
+			}
+			want = append(want, name)
+		}
+	}
+	optionNames(reflect.TypeFor[run.GenerateOptions]())
+	var got []string
+	for name := range configSchema.Defs.Options.Properties {
+		got = append(got, name)
+	}
+	assert.ElementsMatch(t, want, got)
+}
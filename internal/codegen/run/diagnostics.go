@@ -0,0 +1,163 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/willabides/jsonschematogo"
+)
+
+// diagnostic is a warning or error in --diagnostics-format=json output.
+type diagnostic struct {
+	Severity string `json:"severity"`
+	// Code is "unsupported-keyword" for warnings and "error" for errors.
+	Code     string              `json:"code"`
+	Message  string              `json:"message"`
+	Location *diagnosticLocation `json:"location,omitempty"`
+}
+
+type diagnosticLocation struct {
+	// URI is the URL of the schema document.
+	URI     string `json:"uri"`
+	Pointer string `json:"pointer"`
+	// File is the path of a local document relative to the working directory.
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+}
+
+// diagnostics collects the warnings and errors of a run for --diagnostics-file.
+type diagnostics struct {
+	list []diagnostic
+}
+
+func (d *diagnostics) warning(warning *jsonschematogo.SchemaError) {
+	d.list = append(d.list, schemaDiagnostic("warning", "unsupported-keyword", warning))
+}
+
+// error adds a diagnostic for every SchemaError in err, or one without a location when there are
+// none.
+func (d *diagnostics) error(err error) {
+	var schemaErrs []*jsonschematogo.SchemaError
+	collectSchemaErrors(err, &schemaErrs)
+	if len(schemaErrs) == 0 {
+		d.list = append(d.list, diagnostic{Severity: "error", Code: "error", Message: err.Error()})
+		return
+	}
+	for _, schemaErr := range schemaErrs {
+		d.list = append(d.list, schemaDiagnostic("error", "error", schemaErr))
+	}
+}
+
+// collectSchemaErrors appends the outermost SchemaErrors in err's tree to errs.
+func collectSchemaErrors(err error, errs *[]*jsonschematogo.SchemaError) {
+	switch e := err.(type) {
+	case *jsonschematogo.SchemaError:
+		*errs = append(*errs, e)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			collectSchemaErrors(inner, errs)
+		}
+	case interface{ Unwrap() error }:
+		collectSchemaErrors(e.Unwrap(), errs)
+	}
+}
+
+func schemaDiagnostic(severity, code string, err *jsonschematogo.SchemaError) diagnostic {
+	document, _, _ := strings.Cut(err.Location, "#")
+	location := &diagnosticLocation{
+		URI:     document,
+		Pointer: err.Pointer(),
+		Line:    err.Position.Line,
+		Column:  err.Position.Column,
+	}
+	if strings.HasPrefix(document, "file:") {
+		location.File = err.Path()
+	}
+	return diagnostic{
+		Severity: severity,
+		Code:     code,
+		Message:  err.Err.Error(),
+		Location: location,
+	}
+}
+
+// write writes the diagnostics to filename in format, which is json or sarif.
+func (d *diagnostics) write(filename, format string) error {
+	var v any
+	switch format {
+	case "json":
+		list := d.list
+		if list == nil {
+			list = []diagnostic{}
+		}
+		v = map[string]any{"diagnostics": list}
+	case "sarif":
+		v = d.sarif()
+	default:
+		return fmt.Errorf("unknown diagnostics format %q", format)
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0o600)
+}
+
+// sarif returns the diagnostics as a SARIF 2.1.0 log.
+func (d *diagnostics) sarif() map[string]any {
+	results := []any{}
+	for _, diag := range d.list {
+		result := map[string]any{
+			"ruleId":  diag.Code,
+			"level":   diag.Severity,
+			"message": map[string]any{"text": diag.Message},
+		}
+		if diag.Location != nil {
+			uri := diag.Location.URI
+			if diag.Location.File != "" {
+				uri = filepath.ToSlash(diag.Location.File)
+			}
+			physical := map[string]any{
+				"artifactLocation": map[string]any{"uri": uri},
+			}
+			if diag.Location.Line > 0 {
+				physical["region"] = map[string]any{
+					"startLine":   diag.Location.Line,
+					"startColumn": diag.Location.Column,
+				}
+			}
+			result["locations"] = []any{map[string]any{
+				"physicalLocation": physical,
+				"logicalLocations": []any{map[string]any{
+					"fullyQualifiedName": "#" + diag.Location.Pointer,
+				}},
+			}}
+		}
+		results = append(results, result)
+	}
+	return map[string]any{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": []any{map[string]any{
+			"tool": map[string]any{"driver": map[string]any{
+				"name":           "jsonschematogo",
+				"informationUri": "https://github.com/WillAbides/jsonschematogo",
+				"rules": []any{
+					map[string]any{
+						"id":               "unsupported-keyword",
+						"shortDescription": map[string]any{"text": "Schema keyword ignored by the generated types"},
+					},
+					map[string]any{
+						"id":               "error",
+						"shortDescription": map[string]any{"text": "Schema or generation error"},
+					},
+				},
+			}},
+			"results": results,
+		}},
+	}
+}
@@ -0,0 +1,58 @@
+package run
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+// httpAuth resolves the headers, bearer tokens and netrc file for HTTP requests.
+func (o *LoaderOptions) httpAuth() (headers map[string]http.Header, tokens map[string]string, netrc string, _ error) {
+	for _, header := range o.Header {
+		host, name, value, err := parseHeader(header)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if headers == nil {
+			headers = map[string]http.Header{}
+		}
+		if headers[host] == nil {
+			headers[host] = http.Header{}
+		}
+		headers[host].Add(name, value)
+	}
+	for host, envVar := range o.BearerTokenEnv {
+		token := os.Getenv(envVar)
+		if token == "" {
+			return nil, nil, "", fmt.Errorf("bearer token for %s: environment variable %s is empty", host, envVar)
+		}
+		if tokens == nil {
+			tokens = map[string]string{}
+		}
+		tokens[host] = token
+	}
+	if o.Netrc {
+		var err error
+		netrc, err = schemaloader.DefaultNetrcFile()
+		if err != nil {
+			return nil, nil, "", err
+		}
+	}
+	return headers, tokens, netrc, nil
+}
+
+// parseHeader parses a --header value in the form "host=Name: value".
+func parseHeader(header string) (host, name, value string, _ error) {
+	host, field, ok := strings.Cut(header, "=")
+	if ok {
+		name, value, ok = strings.Cut(field, ":")
+	}
+	name = strings.TrimSpace(name)
+	if !ok || host == "" || name == "" {
+		return "", "", "", fmt.Errorf("invalid --header value %q: want HOST=NAME:VALUE", header)
+	}
+	return host, name, strings.TrimSpace(value), nil
+}
@@ -2,68 +2,545 @@ package run
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"maps"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	"github.com/alecthomas/kong"
-	"github.com/willabides/jsonschematogo/internal/codegen"
-	"github.com/willabides/jsonschematogo/internal/schema"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/willabides/jsonschematogo"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+	"github.com/willabides/jsonschematogo/internal/schemavendor"
+	"github.com/willabides/jsonschematogo/internal/watch"
 )
 
 const description = `jsonschematogo converts JSON Schema definitions into Go struct types with proper JSON tags and type mapping.`
 
 type Cmd struct {
-	Files    []string          `kong:"arg,help='JSON/YAML schema files to process'"`
-	Output   string            `kong:"short=o,help='Output file path (defaults to stdout)'"`
-	Package  string            `kong:"short=p,default='gen',help='Package name for generated Go code'"`
-	BaseDir  string            `kong:"group=parsing,help='Base directory for resolving relative schema references'"`
-	URLMap   map[string]string `kong:"placeholder='prefix=directory',group=parsing,help='URL mappings for schema references'"`
-	CACert   string            `kong:"group=parsing,help='CA certificate file for HTTPS connections'"`
-	Insecure bool              `kong:"group=parsing,help='Skip TLS verification for HTTPS connections'"`
-	Version  kong.VersionFlag  `kong:"short=v,help='Output the version and exit'"`
+	Generate GenerateCmd      `kong:"cmd,default='withargs',help='Generate Go code from schemas. This is the default command'"`
+	Vendor   VendorCmd        `kong:"cmd,help='Copy remote schemas into a local directory and lock their checksums'"`
+	Bundle   BundleCmd        `kong:"cmd,help='Write a single self-contained schema with every referenced schema inlined'"`
+	Version  kong.VersionFlag `kong:"short=v,help='Output the version and exit'"`
 }
 
-func (cli *Cmd) Run(k *kong.Context) error {
-	if len(cli.Files) == 0 {
-		return fmt.Errorf("no schema files provided")
+type GenerateCmd struct {
+	Config            string `kong:"placeholder='FILE',help='Config file path (defaults to jsonschematogo.yaml when it exists)'"`
+	GenerateOptions   `kong:"embed"`
+	Watch             bool   `kong:"help='Regenerate whenever a schema file or the config file changes'"`
+	DiagnosticsFile   string `kong:"placeholder='FILE',help='Also write warnings and errors to this file for editors and CI'"`
+	DiagnosticsFormat string `kong:"enum='json,sarif',default='json',help='Format of --diagnostics-file. One of json or sarif'"`
+}
+
+// GenerateOptions are the settings for one generation job. The yaml keys match the flag names so
+// that config file values and flags can be merged field by field.
+type GenerateOptions struct {
+	Files         []string          `yaml:"files" kong:"arg,optional,help='JSON/YAML schema files, directories or glob patterns to process. Use - to read a schema from stdin'"`
+	Include       []string          `yaml:"include" kong:"placeholder='PATTERN',help='Only use files from directories and glob patterns that match one of these patterns'"`
+	Exclude       []string          `yaml:"exclude" kong:"placeholder='PATTERN',help='Skip files from directories and glob patterns that match one of these patterns'"`
+	Output        string            `yaml:"output" kong:"short=o,xor=output,help='Output file path (defaults to stdout)'"`
+	OutputDir     string            `yaml:"output-dir" kong:"xor=output,help='Output directory. Writes one file per schema document'"`
+	Package       string            `yaml:"package" kong:"short=p,default='gen',help='Package name for generated Go code'"`
+	PackageMap    map[string]string `yaml:"package-map" kong:"placeholder='prefix=directory',help='Generate types from schemas under a URL prefix into a package in a subdirectory of --output-dir'"`
+	ImportPath    string            `yaml:"import-path" kong:"help='Go import path of --output-dir. Required with --package-map'"`
+	TypeMap       map[string]string `yaml:"type-map" kong:"placeholder='prefix=importpath[.Type]',help='Use existing Go types for schemas under a URL prefix instead of generating them'"`
+	PropertyOrder string            `yaml:"property-order" kong:"enum='alphabetical,source',default='alphabetical',help='Order of struct fields. Properties with x-order always come first. One of alphabetical or source'"`
+	Optional      string            `yaml:"optional" kong:"enum='pointer,omitzero,optional,nullable',default='pointer',help='How fields for properties that are not required are represented. One of pointer, omitzero, optional or nullable'"`
+	Getters       bool              `yaml:"getters" kong:"help='Generate getter methods that return the default or zero value for absent properties. x-go-getters overrides it for a struct'"`
+	Builders      bool              `yaml:"builders" kong:"help='Generate NewT constructors that take the required fields, WithFoo setters for the other fields and a Ptr helper'"`
+	BaseDir       string            `yaml:"base-dir" kong:"group=parsing,help='Base directory for resolving relative schema references'"`
+	LoaderOptions `yaml:",inline" kong:"embed"`
+	StdinURI      string   `yaml:"stdin-uri" kong:"placeholder='URI',group=parsing,help='URI of a schema read from stdin, used to resolve its relative references (defaults to stdin in the current directory)'"`
+	Lockfile      string   `yaml:"lockfile" kong:"placeholder='FILE',group=parsing,help='Lockfile written by the vendor command. Remote schemas are loaded from the vendor directory and verified against their checksums (defaults to vendored-schemas/jsonschematogo.lock when it exists)'"`
+	Template      []string `yaml:"template" kong:"placeholder='FILE',help='text/template file for an additional output file. It is named after the template without .tmpl and written next to --output or in --output-dir'"`
+	Strict        bool     `yaml:"strict" kong:"help='Fail on schema keywords that the generated types ignore instead of printing warnings'"`
+	DumpIR        bool     `yaml:"dump-ir" kong:"help='Print the intermediate representation of the generated types as JSON instead of generating code'"`
+	Check         bool     `yaml:"check" kong:"help='Check that the output files are up to date instead of writing them. Prints a diff and fails when they are not'"`
+}
+
+// LoaderOptions are the settings for loading remote schemas that every command shares.
+type LoaderOptions struct {
+	URLMap         map[string]string `yaml:"url-map" kong:"placeholder='prefix=directory',group=parsing,help='URL mappings for schema references'"`
+	CACert         string            `yaml:"ca-cert" kong:"group=parsing,help='CA certificate file for HTTPS connections'"`
+	Insecure       bool              `yaml:"insecure" kong:"group=parsing,help='Skip TLS verification for HTTPS connections'"`
+	Header         []string          `yaml:"header" kong:"sep='none',placeholder='HOST=NAME:VALUE',group=parsing,help='HTTP header to send with requests to a host'"`
+	BearerTokenEnv map[string]string `yaml:"bearer-token-env" kong:"placeholder='HOST=ENV_VAR',group=parsing,help='Send the bearer token in an environment variable with requests to a host'"`
+	Netrc          bool              `yaml:"netrc" kong:"group=parsing,help='Use credentials from $NETRC or ~/.netrc for HTTP basic authentication'"`
+	HTTPTimeout    time.Duration     `yaml:"http-timeout" kong:"default='15s',group=parsing,help='Timeout for each HTTP request'"`
+	HTTPRetries    int               `yaml:"http-retries" kong:"default='2',group=parsing,help='How many times to retry HTTP requests after a network error or a 429 or 5xx response'"`
+	CacheDir       string            `yaml:"cache-dir" kong:"group=parsing,help='Directory for caching remote schemas (defaults to jsonschematogo in the user cache directory)'"`
+	Offline        bool              `yaml:"offline" kong:"xor=offline,group=parsing,help='Load remote schemas only from the cache. Fails when a schema is not cached'"`
+	Refresh        bool              `yaml:"refresh" kong:"xor=offline,group=parsing,help='Download remote schemas again instead of revalidating cached copies'"`
+}
+
+func (cli *GenerateCmd) Run(k *kong.Context, stdin io.Reader) error {
+	in := &stdinReader{r: stdin}
+	if cli.Watch {
+		return cli.watch(k, in)
 	}
+	_, err := cli.runJobs(k, in)
+	return err
+}
+
+// stdinReader reads stdin once so that the schema can be used by every job and every run in watch
+// mode.
+type stdinReader struct {
+	r    io.Reader
+	once sync.Once
+	data []byte
+	err  error
+}
+
+func (s *stdinReader) read() ([]byte, error) {
+	s.once.Do(func() {
+		s.data, s.err = io.ReadAll(s.r)
+	})
+	return s.data, s.err
+}
 
-	// Load all schemas and build the map for $ref resolution
-	schemas, err := schema.LoadAllSchemas(cli.Files)
+// runJobs runs every job and returns the local files the jobs depend on. The files are returned
+// even when a job fails so that they can be watched for a fix. Warnings are printed to stderr and,
+// with --diagnostics-file, written there along with the error.
+func (cli *GenerateCmd) runJobs(k *kong.Context, stdin *stdinReader) ([]string, error) {
+	diags := &diagnostics{}
+	onWarning := func(warning *jsonschematogo.SchemaError) {
+		fmt.Fprintf(k.Stderr, "warning: %v\n", warning)
+		diags.warning(warning)
+	}
+	files, err := cli.runJobsWithWarnings(k, stdin, onWarning)
+	if cli.DiagnosticsFile == "" {
+		return files, err
+	}
 	if err != nil {
-		return err
+		diags.error(err)
+	}
+	writeErr := diags.write(cli.DiagnosticsFile, cli.DiagnosticsFormat)
+	if writeErr != nil {
+		return files, errors.Join(err, fmt.Errorf("writing diagnostics: %w", writeErr))
+	}
+	return files, err
+}
+
+func (cli *GenerateCmd) runJobsWithWarnings(
+	k *kong.Context,
+	stdin *stdinReader,
+	onWarning func(*jsonschematogo.SchemaError),
+) ([]string, error) {
+	var files []string
+	configFile := cli.configFile()
+	if configFile != "" {
+		files = append(files, configFile)
+	}
+	jobs, err := cli.jobs(k)
+	if err != nil {
+		return files, err
+	}
+	for _, job := range jobs {
+		for _, file := range job.Files {
+			if file != "-" {
+				files = append(files, file)
+			}
+		}
+		documents, jobErr := job.generate(k.Stdout, stdin, onWarning)
+		files = append(files, documents...)
+		if jobErr != nil {
+			return files, jobErr
+		}
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// watch runs the jobs every time one of the files they depend on changes until interrupted. Errors
+// are reported without stopping.
+func (cli *GenerateCmd) watch(k *kong.Context, stdin *stdinReader) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := watch.Run(ctx, nil, func() []string {
+		files, runErr := cli.runJobs(k, stdin)
+		if runErr != nil {
+			k.Errorf("%v", runErr)
+		}
+		return files
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// generate runs a single generation job. It returns the paths of the local schema documents that
+// were loaded.
+func (o *GenerateOptions) generate(
+	stdout io.Writer,
+	stdin *stdinReader,
+	onWarning func(*jsonschematogo.SchemaError),
+) ([]string, error) {
+	if len(o.Files) == 0 {
+		return nil, fmt.Errorf("no schema files provided")
+	}
+	if o.Output != "" && o.OutputDir != "" {
+		return nil, fmt.Errorf("output and output-dir can't be used together")
 	}
 
-	// Generate Go code for each entry point
-	var output bytes.Buffer
-	for _, file := range cli.Files {
-		sch := schemas[file]
-		opts := &codegen.Options{
-			PackageName: cli.Package,
-			Schemas:     schemas,
+	var documents []string
+	lockfile := o.lockfile()
+	if lockfile != "" {
+		documents = append(documents, lockfile)
+	}
+	loaderOpts, err := o.loaderOptions(lockfile)
+	if err != nil {
+		return documents, err
+	}
+	cfg := jsonschematogo.Config{
+		Files:         slices.Clone(o.Files),
+		Include:       o.Include,
+		Exclude:       o.Exclude,
+		URLMap:        loaderOpts.Mappings,
+		CACert:        loaderOpts.CACert,
+		Insecure:      loaderOpts.Insecure,
+		CacheDir:      loaderOpts.CacheDir,
+		Offline:       loaderOpts.Offline,
+		Refresh:       loaderOpts.Refresh,
+		Checksums:     loaderOpts.Checksums,
+		Headers:       loaderOpts.Headers,
+		BearerTokens:  loaderOpts.BearerTokens,
+		Netrc:         loaderOpts.Netrc,
+		Timeout:       loaderOpts.Timeout,
+		Retries:       loaderOpts.Retries,
+		PackageName:   o.Package,
+		PropertyOrder: jsonschematogo.PropertyOrder(o.PropertyOrder),
+		Optional:      jsonschematogo.OptionalStrategy(o.Optional),
+		Getters:       o.Getters,
+		Builders:      o.Builders,
+		Strict:        o.Strict,
+		OnWarning:     onWarning,
+	}
+	if len(o.Template) > 0 {
+		documents = append(documents, o.Template...)
+		cfg.Templates, err = o.templates()
+		if err != nil {
+			return documents, err
+		}
+	}
+	if slices.Contains(cfg.Files, "-") {
+		stdinURI, err := o.stdinURI()
+		if err != nil {
+			return nil, err
+		}
+		data, err := stdin.read()
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+		cfg.Sources = map[string][]byte{stdinURI: data}
+		for i, file := range cfg.Files {
+			if file == "-" {
+				cfg.Files[i] = stdinURI
+			}
 		}
-		genErr := codegen.GenerateGoCode(&output, sch, opts)
-		if genErr != nil {
-			return fmt.Errorf("failed to generate code for %s: %w", file, genErr)
+	}
+
+	schemas, err := jsonschematogo.Load(context.Background(), cfg)
+	if err != nil {
+		return documents, err
+	}
+	for _, sch := range schemas {
+		documents = append(documents, sch.LocalFiles()...)
+	}
+	for prefix, value := range o.TypeMap {
+		externalType, typeErr := parseExternalType(prefix, value)
+		if typeErr != nil {
+			return documents, typeErr
 		}
+		cfg.ExternalTypes = append(cfg.ExternalTypes, externalType)
+	}
+
+	if o.DumpIR {
+		return documents, o.dumpIR(stdout, schemas, cfg)
 	}
+	files, err := o.render(schemas, cfg)
+	if err != nil {
+		return documents, err
+	}
+	if o.Check {
+		return documents, checkFiles(stdout, files)
+	}
+	return documents, writeFiles(stdout, files)
+}
+
+// defaultCacheDir returns the cache directory used when --cache-dir isn't set. It is empty when
+// the user cache directory is unknown.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "jsonschematogo")
+}
 
-	if cli.Output == "" {
-		_, writeErr := fmt.Fprintln(k.Stdout, output.String())
-		return writeErr
+// mergeURLMap returns urlMap with the mappings from a lockfile added. Mappings in urlMap take
+// precedence, so locked prefixes that are under a prefix in urlMap are left out.
+func mergeURLMap(urlMap, locked map[string]string) map[string]string {
+	merged := maps.Clone(urlMap)
+	if merged == nil {
+		merged = map[string]string{}
 	}
+	for prefix, dir := range locked {
+		overridden := false
+		for userPrefix := range urlMap {
+			overridden = overridden || strings.HasPrefix(prefix, userPrefix)
+		}
+		if !overridden {
+			merged[prefix] = dir
+		}
+	}
+	return merged
+}
 
-	err = os.WriteFile(cli.Output, output.Bytes(), 0o600)
+// loaderOptions returns the options for loading schemas. When lockfile isn't empty, remote schemas
+// are loaded from its vendor directory and verified against its checksums.
+func (o *LoaderOptions) loaderOptions(lockfile string) (*schemaloader.Options, error) {
+	cacheDir := o.CacheDir
+	if cacheDir == "" {
+		cacheDir = defaultCacheDir()
+	}
+	headers, tokens, netrc, err := o.httpAuth()
 	if err != nil {
-		return fmt.Errorf("writing to file: %w", err)
+		return nil, err
+	}
+	opts := &schemaloader.Options{
+		Mappings:     o.URLMap,
+		CACert:       o.CACert,
+		Insecure:     o.Insecure,
+		CacheDir:     cacheDir,
+		Offline:      o.Offline,
+		Refresh:      o.Refresh,
+		Headers:      headers,
+		BearerTokens: tokens,
+		Netrc:        netrc,
+		Timeout:      o.HTTPTimeout,
+		Retries:      o.HTTPRetries,
+	}
+	if lockfile != "" {
+		lock, err := schemavendor.ReadLock(lockfile)
+		if err != nil {
+			return nil, err
+		}
+		opts.Mappings = mergeURLMap(opts.Mappings, lock.URLMap)
+		opts.Checksums = lock.Checksums()
+	}
+	return opts, nil
+}
+
+// lockfile returns the lockfile to use or an empty string when there is none.
+func (o *GenerateOptions) lockfile() string {
+	return lockfile(o.Lockfile)
+}
+
+// lockfile returns name, or the default lockfile when name is empty and the default exists.
+func lockfile(name string) string {
+	if name != "" {
+		return name
+	}
+	lockfile := filepath.Join(schemavendor.DefaultDir, schemavendor.LockfileName)
+	_, err := os.Stat(lockfile)
+	if err != nil {
+		return ""
+	}
+	return lockfile
+}
+
+// stdinURI returns the URI of a schema read from stdin.
+func (o *GenerateOptions) stdinURI() (string, error) {
+	if o.StdinURI != "" {
+		return schemaloader.ToURL(o.StdinURI)
+	}
+	return schemaloader.ToURL("stdin")
+}
+
+// render generates the output for a job. The result maps output file paths to their content. The
+// empty path is stdout.
+func (o *GenerateOptions) render(schemas []*jsonschematogo.Schema, cfg jsonschematogo.Config) (map[string][]byte, error) {
+	cfg, err := o.outputConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	files, err := jsonschematogo.Render(schemas, cfg)
+	if err != nil {
+		return nil, err
+	}
+	output := make(map[string][]byte, len(files))
+	for name, content := range files {
+		output[o.outputPath(name, cfg)] = content
+	}
+	return output, nil
+}
+
+// outputPath returns the path of a rendered file. Without --output-dir, everything except template
+// output goes to --output.
+func (o *GenerateOptions) outputPath(name string, cfg jsonschematogo.Config) string {
+	if o.OutputDir != "" {
+		return filepath.Join(o.OutputDir, filepath.FromSlash(name))
+	}
+	if _, ok := cfg.Templates[name]; ok {
+		return filepath.Join(filepath.Dir(o.Output), name)
+	}
+	return o.Output
+}
+
+// templates reads the --template files keyed by the name of the file they generate.
+func (o *GenerateOptions) templates() (map[string]string, error) {
+	if o.Output == "" && o.OutputDir == "" {
+		return nil, fmt.Errorf("--template requires --output or --output-dir")
+	}
+	templates := make(map[string]string, len(o.Template))
+	for _, filename := range o.Template {
+		name := strings.TrimSuffix(filepath.Base(filename), ".tmpl")
+		if _, ok := templates[name]; ok {
+			return nil, fmt.Errorf("more than one template generates %s", name)
+		}
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = string(content)
+	}
+	return templates, nil
+}
+
+// outputConfig adds the settings for --output-dir to cfg.
+func (o *GenerateOptions) outputConfig(cfg jsonschematogo.Config) (jsonschematogo.Config, error) {
+	if o.OutputDir == "" {
+		return cfg, nil
+	}
+	if len(o.PackageMap) > 0 && o.ImportPath == "" {
+		return cfg, fmt.Errorf("--import-path is required with --package-map")
+	}
+	cfg.SplitFiles = true
+	cfg.ImportPath = o.ImportPath
+	for prefix, dir := range o.PackageMap {
+		cfg.Packages = append(cfg.Packages, jsonschematogo.Package{
+			URLPrefix: prefix,
+			Dir:       dir,
+		})
+	}
+	return cfg, nil
+}
+
+// dumpIR writes the intermediate representation of the job's code to stdout as JSON.
+func (o *GenerateOptions) dumpIR(stdout io.Writer, schemas []*jsonschematogo.Schema, cfg jsonschematogo.Config) error {
+	cfg, err := o.outputConfig(cfg)
+	if err != nil {
+		return err
+	}
+	ir, err := jsonschematogo.BuildIR(schemas, cfg)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(ir)
+}
+
+func writeFiles(stdout io.Writer, files map[string][]byte) error {
+	for _, filename := range slices.Sorted(maps.Keys(files)) {
+		content := files[filename]
+		if filename == "" {
+			_, err := stdout.Write(content)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		err := os.MkdirAll(filepath.Dir(filename), 0o700)
+		if err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+		err = os.WriteFile(filename, content, 0o600)
+		if err != nil {
+			return fmt.Errorf("writing to file: %w", err)
+		}
 	}
 	return nil
 }
 
+// checkFiles compares files with what is on disk and prints a unified diff for every file that is
+// out of date.
+func checkFiles(stdout io.Writer, files map[string][]byte) error {
+	var stale []string
+	for _, filename := range slices.Sorted(maps.Keys(files)) {
+		if filename == "" {
+			return fmt.Errorf("--check requires --output or --output-dir")
+		}
+		want := files[filename]
+		got, err := os.ReadFile(filename)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("reading %s: %w", filename, err)
+		}
+		if bytes.Equal(got, want) {
+			continue
+		}
+		stale = append(stale, filename)
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(got)),
+			B:        difflib.SplitLines(string(want)),
+			FromFile: filename,
+			ToFile:   filename + " (generated)",
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(stdout, diff)
+		if err != nil {
+			return err
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("generated code is out of date: %s", strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// parseExternalType parses a --type-map value in the form "import/path" or "import/path.TypeName".
+// A type name is only split off when it is exported, so "gopkg.in/yaml.v3" is a plain import path.
+func parseExternalType(prefix, value string) (jsonschematogo.ExternalType, error) {
+	externalType := jsonschematogo.ExternalType{
+		URLPrefix:  prefix,
+		ImportPath: value,
+	}
+	lastSlash := strings.LastIndex(value, "/")
+	dot := strings.LastIndex(value, ".")
+	if dot > lastSlash {
+		typeName := value[dot+1:]
+		if typeName != "" && unicode.IsUpper([]rune(typeName)[0]) {
+			externalType.ImportPath = value[:dot]
+			externalType.TypeName = typeName
+		}
+	}
+	if externalType.ImportPath == "" {
+		return jsonschematogo.ExternalType{}, fmt.Errorf("invalid --type-map value %q: missing import path", value)
+	}
+	return externalType, nil
+}
+
 func Run(args []string, opts []kong.Option) (exitCode int) {
 	done := false
 	errForceDone := fmt.Errorf("force done")
+	// stdin is bound first so that callers can replace it
+	opts = append([]kong.Option{kong.BindTo(os.Stdin, (*io.Reader)(nil))}, opts...)
 	opts = append(
 		opts,
 		kong.Description(description),
@@ -1,11 +1,19 @@
 package run_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/willabides/jsonschematogo/internal/codegen/run/testrun"
 )
 
@@ -24,6 +32,230 @@ func TestRun(t *testing.T) {
 		result := testrun.Run("--undefined-arg")
 		assert.NotZero(t, result.ExitCode)
 	})
+
+	t.Run("directory with colliding type names", func(t *testing.T) {
+		result := testrun.Run("../testdata/schemas/collisions")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "type name Person is already used by")
+		assert.Contains(t, result.Stderr, "collisions/a/person.yaml")
+		assert.Contains(t, result.Stderr, "collisions/b/person.yaml")
+	})
+}
+
+func TestCheck(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.go")
+	result := testrun.Run("--output", output, "../testdata/schemas/primitives.yaml")
+	result.AssertSuccess(t)
+
+	t.Run("up to date", func(t *testing.T) {
+		result := testrun.Run("--check", "--output", output, "../testdata/schemas/primitives.yaml")
+		result.AssertSuccess(t)
+		assert.Empty(t, result.Stdout)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		stale := filepath.Join(t.TempDir(), "output.go")
+		require.NoError(t, os.WriteFile(stale, []byte("package gen\n"), 0o600))
+		result := testrun.Run("--check", "--output", stale, "../testdata/schemas/primitives.yaml")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stdout, "+++ "+stale+" (generated)")
+		assert.Contains(t, result.Stderr, "generated code is out of date")
+		content, err := os.ReadFile(stale)
+		require.NoError(t, err)
+		assert.Equal(t, "package gen\n", string(content))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "output.go")
+		result := testrun.Run("--check", "--output", missing, "../testdata/schemas/primitives.yaml")
+		assert.NotZero(t, result.ExitCode)
+		assert.NoFileExists(t, missing)
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		result := testrun.Run("--check", "../testdata/schemas/primitives.yaml")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "--check requires --output or --output-dir")
+	})
+}
+
+func TestStdout(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.go")
+	result := testrun.Run("--output", output, "../testdata/schemas/primitives.yaml")
+	result.AssertSuccess(t)
+	want, err := os.ReadFile(output)
+	require.NoError(t, err)
+
+	result = testrun.Run("../testdata/schemas/primitives.yaml")
+	result.AssertSuccess(t)
+	assert.Equal(t, string(want), result.Stdout)
+}
+
+func TestStdin(t *testing.T) {
+	company, err := os.ReadFile("../testdata/schemas/company/company.yaml")
+	require.NoError(t, err)
+
+	t.Run("stdin uri", func(t *testing.T) {
+		result := testrun.RunStdin(
+			bytes.NewReader(company),
+			"--stdin-uri", "../testdata/schemas/company/stdin.yaml", "-",
+		)
+		result.AssertSuccess(t)
+		assert.Contains(t, result.Stdout, "type Company struct")
+		assert.Contains(t, result.Stdout, "type Person struct")
+	})
+
+	t.Run("relative ref without stdin uri", func(t *testing.T) {
+		result := testrun.RunStdin(bytes.NewReader(company), "-")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "person.yaml")
+	})
+}
+
+func TestOffline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"title": "Person", "type": "object", "properties": {"name": {"type": "string"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	company := []byte(`{"title": "Company", "type": "object", "properties": {"ceo": {"$ref": "` +
+		server.URL + `/person.json"}}}`)
+	cacheDir := t.TempDir()
+
+	result := testrun.RunStdin(bytes.NewReader(company), "--cache-dir", cacheDir, "-")
+	result.AssertSuccess(t)
+	want := result.Stdout
+	assert.Contains(t, want, "type Person struct")
+	server.Close()
+
+	t.Run("cached", func(t *testing.T) {
+		result := testrun.RunStdin(bytes.NewReader(company), "--cache-dir", cacheDir, "--offline", "-")
+		result.AssertSuccess(t)
+		assert.Equal(t, want, result.Stdout)
+	})
+
+	t.Run("not cached", func(t *testing.T) {
+		result := testrun.RunStdin(bytes.NewReader(company), "--cache-dir", t.TempDir(), "--offline", "-")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "/person.json is not cached and can't be fetched offline")
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		result := testrun.RunStdin(bytes.NewReader(company), "--http-retries", "0", "--cache-dir", cacheDir, "--refresh", "-")
+		assert.NotZero(t, result.ExitCode)
+	})
+}
+
+func TestVendor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"title": "Person", "type": "object", "properties": {"name": {"type": "string"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "company.json")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`{"title": "Company", "type": "object", "properties": {"ceo": {"$ref": "`+
+		server.URL+`/person.json"}}}`), 0o600))
+	vendorDir := filepath.Join(dir, "vendored")
+	lockfile := filepath.Join(vendorDir, "jsonschematogo.lock")
+
+	result := testrun.Run("vendor", "--cache-dir", t.TempDir(), "--dir", vendorDir, schemaFile)
+	result.AssertSuccess(t)
+	assert.Contains(t, result.Stdout, "Vendored 1 schemas")
+	server.Close()
+
+	t.Run("generate from vendored schemas", func(t *testing.T) {
+		result := testrun.Run("--cache-dir", t.TempDir(), "--lockfile", lockfile, schemaFile)
+		result.AssertSuccess(t)
+		assert.Contains(t, result.Stdout, "type Person struct")
+	})
+
+	t.Run("changed schema", func(t *testing.T) {
+		tampered := filepath.Join(dir, "tampered")
+		result := testrun.Run("vendor", "--http-retries", "0", "--cache-dir", t.TempDir(), "--dir", tampered, schemaFile)
+		assert.NotZero(t, result.ExitCode, "vendoring needs the server")
+
+		require.NoError(t, os.CopyFS(tampered, os.DirFS(vendorDir)))
+		matches, err := filepath.Glob(filepath.Join(tampered, "*", "person.json"))
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		require.NoError(t, os.WriteFile(matches[0], []byte(`{"title": "Person", "type": "object"}`), 0o600))
+		result = testrun.Run("--lockfile", filepath.Join(tampered, "jsonschematogo.lock"), schemaFile)
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "checksum mismatch for "+server.URL+"/person.json")
+	})
+
+	t.Run("url-map overrides lockfile", func(t *testing.T) {
+		serverURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+		override := filepath.Join(dir, "override")
+		require.NoError(t, os.MkdirAll(filepath.Join(override, serverURL.Port()), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(override, serverURL.Port(), "person.json"),
+			[]byte(`{"title": "Person", "type": "object"}`), 0o600))
+		// The mapped prefix contains the locked one, so the changed copy is loaded and fails the
+		// checksum instead of the vendored copy.
+		hostPrefix := strings.TrimSuffix(server.URL, serverURL.Port())
+		result := testrun.Run("--lockfile", lockfile, "--url-map", hostPrefix+"="+override, schemaFile)
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "checksum mismatch for "+server.URL+"/person.json")
+	})
+
+	t.Run("remote schema missing from lockfile", func(t *testing.T) {
+		other := filepath.Join(dir, "other.json")
+		require.NoError(t, os.WriteFile(other, []byte(`{"title": "Other", "type": "object", "properties": {"a": {"$ref": "`+
+			server.URL+`/other.json"}}}`), 0o600))
+		result := testrun.Run("--lockfile", lockfile, other)
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, server.URL+"/other.json has no checksum in the lockfile")
+	})
+}
+
+func TestHTTPAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("X-Team") != "a, b" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"title": "Person", "type": "object", "properties": {"name": {"type": "string"}}}`))
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	company := []byte(`{"title": "Company", "type": "object", "properties": {"ceo": {"$ref": "` +
+		server.URL + `/person.json"}}}`)
+	t.Setenv("REGISTRY_TOKEN", "secret")
+
+	t.Run("authorized", func(t *testing.T) {
+		result := testrun.RunStdin(
+			bytes.NewReader(company),
+			"--cache-dir", t.TempDir(),
+			"--bearer-token-env", serverURL.Host+"=REGISTRY_TOKEN",
+			"--header", serverURL.Host+"=X-Team: a, b",
+			"-",
+		)
+		result.AssertSuccess(t)
+		assert.Contains(t, result.Stdout, "type Person struct")
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		result := testrun.RunStdin(bytes.NewReader(company), "--cache-dir", t.TempDir(), "-")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "returned status code 401")
+	})
+
+	t.Run("empty token", func(t *testing.T) {
+		result := testrun.RunStdin(
+			bytes.NewReader(company),
+			"--bearer-token-env", serverURL.Host+"=MISSING_REGISTRY_TOKEN",
+			"-",
+		)
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "environment variable MISSING_REGISTRY_TOKEN is empty")
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		result := testrun.RunStdin(bytes.NewReader(company), "--header", "X-Team: a", "-")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, `invalid --header value "X-Team: a": want HOST=NAME:VALUE`)
+	})
 }
 
 func TestURL(t *testing.T) {
@@ -31,3 +263,145 @@ func TestURL(t *testing.T) {
 	assert.NoError(t, err)
 	fmt.Println(u)
 }
+
+func TestBundle(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "person.json"), []byte(`{"type": "object", "properties": {"name": {"type": "string"}}}`), 0o600))
+	entry := filepath.Join(dir, "company.json")
+	require.NoError(t, os.WriteFile(entry, []byte(`{"type": "object", "properties": {"ceo": {"$ref": "person.json"}}}`), 0o600))
+
+	t.Run("json", func(t *testing.T) {
+		result := testrun.Run("bundle", entry)
+		result.AssertSuccess(t)
+		assert.JSONEq(t, `{
+			"type": "object",
+			"properties": {"ceo": {"$ref": "#/$defs/person"}},
+			"$defs": {"person": {"type": "object", "properties": {"name": {"type": "string"}}}}
+		}`, result.Stdout)
+	})
+
+	t.Run("yaml output file", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "bundled.yaml")
+		result := testrun.Run("bundle", "-o", output, entry)
+		result.AssertSuccess(t)
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "$ref: '#/$defs/person'\n")
+
+		result = testrun.Run(output)
+		result.AssertSuccess(t)
+		assert.Contains(t, result.Stdout, "type Person struct")
+	})
+}
+
+func TestDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	schemaFile := filepath.Join(dir, "pet.yaml")
+	require.NoError(t, os.WriteFile(schemaFile, []byte("type: object\nproperties:\n  pet:\n    oneOf:\n      - type: string\n"), 0o600))
+	schemaURI := (&url.URL{Scheme: "file", Path: filepath.ToSlash(schemaFile)}).String()
+
+	t.Run("json", func(t *testing.T) {
+		diagnosticsFile := filepath.Join(t.TempDir(), "diagnostics.json")
+		result := testrun.Run("--diagnostics-file", diagnosticsFile, schemaFile)
+		assert.Zero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "warning: ")
+		content, err := os.ReadFile(diagnosticsFile)
+		require.NoError(t, err)
+		assert.JSONEq(t, fmt.Sprintf(`{"diagnostics": [{
+			"severity": "warning",
+			"code": "unsupported-keyword",
+			"message": "oneOf is not supported and is ignored",
+			"location": {"uri": %q, "pointer": "/properties/pet/oneOf", "file": %q, "line": 4, "column": 5}
+		}]}`, schemaURI, schemaFile), string(content))
+	})
+
+	t.Run("strict json", func(t *testing.T) {
+		diagnosticsFile := filepath.Join(t.TempDir(), "diagnostics.json")
+		result := testrun.Run("--strict", "--diagnostics-file", diagnosticsFile, schemaFile)
+		assert.NotZero(t, result.ExitCode)
+		content, err := os.ReadFile(diagnosticsFile)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"severity": "error"`)
+		assert.Contains(t, string(content), `"pointer": "/properties/pet/oneOf"`)
+	})
+
+	t.Run("sarif", func(t *testing.T) {
+		diagnosticsFile := filepath.Join(t.TempDir(), "diagnostics.sarif")
+		result := testrun.Run("--diagnostics-format", "sarif", "--diagnostics-file", diagnosticsFile, "nonexistent.yaml")
+		assert.NotZero(t, result.ExitCode)
+		content, err := os.ReadFile(diagnosticsFile)
+		require.NoError(t, err)
+		var sarif struct {
+			Version string `json:"version"`
+			Runs    []struct {
+				Results []struct {
+					RuleID    string `json:"ruleId"`
+					Level     string `json:"level"`
+					Locations []any  `json:"locations"`
+				} `json:"results"`
+			} `json:"runs"`
+		}
+		require.NoError(t, json.Unmarshal(content, &sarif))
+		assert.Equal(t, "2.1.0", sarif.Version)
+		require.Len(t, sarif.Runs, 1)
+		require.Len(t, sarif.Runs[0].Results, 1)
+		assert.Equal(t, "error", sarif.Runs[0].Results[0].RuleID)
+		assert.Equal(t, "error", sarif.Runs[0].Results[0].Level)
+		assert.Empty(t, sarif.Runs[0].Results[0].Locations)
+	})
+}
+
+func TestDumpIR(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "output.go")
+	result := testrun.Run("--dump-ir", "-o", output, "../testdata/schemas/primitives.yaml")
+	result.AssertSuccess(t)
+	assert.NoFileExists(t, output)
+	var ir struct {
+		Files []struct {
+			Name  string `json:"name"`
+			Types []struct {
+				Name   string `json:"name"`
+				Kind   string `json:"kind"`
+				Origin string `json:"origin"`
+			} `json:"types"`
+		} `json:"files"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Stdout), &ir))
+	require.Len(t, ir.Files, 1)
+	assert.Equal(t, "gen.go", ir.Files[0].Name)
+	require.NotEmpty(t, ir.Files[0].Types)
+	assert.Equal(t, "struct", ir.Files[0].Types[0].Kind)
+	assert.True(t, strings.HasSuffix(ir.Files[0].Types[0].Origin, "primitives.yaml#"), ir.Files[0].Types[0].Origin)
+}
+
+func TestTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templateFile := filepath.Join(dir, "columns.go.tmpl")
+	require.NoError(t, os.WriteFile(templateFile, []byte(`package {{(index .Files 0).PackageName}}
+{{range .Types}}
+func ({{.Name}}) Columns() []string {
+	return []string{ {{- range .Fields}}"{{snakeCase .Name}}", {{end -}} }
+}
+{{end}}`), 0o600))
+
+	t.Run("output", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "types.go")
+		result := testrun.Run("--template", templateFile, "-o", output, "../testdata/schemas/x_go_name.yaml")
+		result.AssertSuccess(t)
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(output), "columns.go"))
+		require.NoError(t, err)
+		assert.Equal(t, `package gen
+
+func (XGoNameTest) Columns() []string {
+	return []string{"api_key_override", "normal_field", "user_id_override"}
+}
+`, string(content))
+		assert.FileExists(t, output)
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		result := testrun.Run("--template", templateFile, "../testdata/schemas/x_go_name.yaml")
+		assert.NotZero(t, result.ExitCode)
+		assert.Contains(t, result.Stderr, "--template requires --output or --output-dir")
+	})
+}
@@ -2,6 +2,7 @@ package testrun
 
 import (
 	"bytes"
+	"io"
 	"testing"
 
 	"github.com/alecthomas/kong"
@@ -24,11 +25,19 @@ func (r Result) AssertSuccess(t *testing.T) {
 type Runner struct{}
 
 func Run(args ...string) Result {
+	return RunStdin(nil, args...)
+}
+
+// RunStdin is like Run but reads stdin from the given reader.
+func RunStdin(stdin io.Reader, args ...string) Result {
 	var stdout, stderr bytes.Buffer
 	opts := []kong.Option{
 		kong.Name("jsonschematogo"),
 		kong.Writers(&stdout, &stderr),
 	}
+	if stdin != nil {
+		opts = append(opts, kong.BindTo(stdin, (*io.Reader)(nil)))
+	}
 	exitCode := run.Run(args, opts)
 	return Result{
 		Stdout:   stdout.String(),
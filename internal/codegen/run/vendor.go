@@ -0,0 +1,81 @@
+package run
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"github.com/alecthomas/kong"
+	"github.com/willabides/jsonschematogo/internal/inputs"
+	"github.com/willabides/jsonschematogo/internal/schemavendor"
+)
+
+// VendorCmd copies the remote schemas referenced by the entry schemas into a local directory and
+// writes a lockfile with their checksums. Generation uses the lockfile to load the vendored copies
+// and to verify that they haven't changed.
+type VendorCmd struct {
+	Config        string   `kong:"placeholder='FILE',help='Config file path (defaults to jsonschematogo.yaml when it exists). Schemas from every job are vendored'"`
+	Dir           string   `kong:"default='vendored-schemas',help='Directory for the vendored schemas and the lockfile'"`
+	Files         []string `kong:"arg,optional,help='JSON/YAML schema files, directories or glob patterns to vendor remote references for'"`
+	Include       []string `kong:"placeholder='PATTERN',help='Only use files from directories and glob patterns that match one of these patterns'"`
+	Exclude       []string `kong:"placeholder='PATTERN',help='Skip files from directories and glob patterns that match one of these patterns'"`
+	LoaderOptions `kong:"embed"`
+}
+
+func (cli *VendorCmd) Run(k *kong.Context) error {
+	opts := GenerateOptions{
+		Files:         cli.Files,
+		Include:       cli.Include,
+		Exclude:       cli.Exclude,
+		LoaderOptions: cli.LoaderOptions,
+	}
+	jobs, err := loadJobs(k, configFile(cli.Config), &opts)
+	if err != nil {
+		return err
+	}
+	documents := map[string][]byte{}
+	for _, job := range jobs {
+		found, err := job.crawl()
+		if err != nil {
+			return err
+		}
+		maps.Copy(documents, found)
+	}
+	lock, err := schemavendor.Write(cli.Dir, documents)
+	if err != nil {
+		return err
+	}
+	lockfile := filepath.Join(cli.Dir, schemavendor.LockfileName)
+	_, err = fmt.Fprintf(k.Stdout, "Vendored %d schemas. Wrote %s\n", len(lock.Schemas), lockfile)
+	if err != nil {
+		return err
+	}
+	if cli.Dir != schemavendor.DefaultDir {
+		_, err = fmt.Fprintf(k.Stdout, "Generate with --lockfile %s to use them\n", lockfile)
+	}
+	return err
+}
+
+// crawl returns the remote documents that a job's entry schemas depend on.
+func (o *GenerateOptions) crawl() (map[string][]byte, error) {
+	if len(o.Files) == 0 {
+		return nil, fmt.Errorf("no schema files provided")
+	}
+	entries, err := inputs.Expand(o.Files, &inputs.Options{
+		Include: o.Include,
+		Exclude: o.Exclude,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(entries, "-") {
+		return nil, fmt.Errorf("schemas from stdin can't be vendored")
+	}
+	// The lockfile isn't used because vendoring replaces it.
+	opts, err := o.loaderOptions("")
+	if err != nil {
+		return nil, err
+	}
+	return schemavendor.Crawl(entries, opts)
+}
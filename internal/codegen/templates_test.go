@@ -0,0 +1,50 @@
+package codegen_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/codegen"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	model := &codegen.Model{
+		Files: []*codegen.File{{
+			Name:        "gen.go",
+			PackageName: "gen",
+			Types: []*codegen.TypeDecl{{
+				Name:       "UserAccount",
+				Kind:       codegen.TypeDeclStruct,
+				Extensions: map[string]any{"x-table-name": "accounts"},
+				Fields: []*codegen.Field{
+					{Name: "UserID", JSONName: "user_id", Type: &codegen.TypeRef{Kind: codegen.TypeRefNamed, Name: "string"}},
+					{Name: "Tags", JSONName: "tags", Type: &codegen.TypeRef{
+						Kind: codegen.TypeRefSlice,
+						Elem: &codegen.TypeRef{Kind: codegen.TypeRefNamed, Name: "Tag", Package: "example.com/tags"},
+					}},
+				},
+			}},
+		}},
+	}
+
+	t.Run("text", func(t *testing.T) {
+		got, err := codegen.RenderTemplate(model, "columns.txt", `{{range .Types}}{{index .Extensions "x-table-name"}}:{{range .Fields}} {{snakeCase .Name}} {{.Type}}{{end}}{{end}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "accounts: user_id string tags []tags.Tag", string(got))
+	})
+
+	t.Run("go", func(t *testing.T) {
+		got, err := codegen.RenderTemplate(model, "columns.go", `package gen
+{{range .Types}}
+func ({{.Name}}) Columns() []string { return []string{ {{range .Fields}}"{{.JSONName}}",{{end}} } }
+{{end}}`)
+		require.NoError(t, err)
+		assert.Equal(t, "package gen\n\nfunc (UserAccount) Columns() []string { return []string{\"user_id\", \"tags\"} }\n", string(got))
+	})
+
+	t.Run("invalid go", func(t *testing.T) {
+		_, err := codegen.RenderTemplate(model, "broken.go", "package gen\nfunc {{(index .Types 0).Name}} {")
+		require.ErrorContains(t, err, "template output is not valid Go")
+	})
+}
@@ -0,0 +1,68 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Address struct {
+	Street *string `json:"street"`
+}
+
+// NewAddress returns a new Address with its required fields set.
+func NewAddress() *Address {
+	return &Address{}
+}
+
+// WithStreet sets Street and returns a.
+func (a *Address) WithStreet(value string) *Address {
+	a.Street = &value
+	return a
+}
+
+type BuildersTest struct {
+	URL   string   `json:"URL"`
+	Count *int     `json:"count"`
+	Home  Address  `json:"home"`
+	Id    string   `json:"id"`
+	Name  *string  `json:"name"`
+	Tags  []string `json:"tags"`
+	Type  string   `json:"type"`
+	Work  *Address `json:"work"`
+}
+
+// NewBuildersTest returns a new BuildersTest with its required fields set.
+func NewBuildersTest(url string, home Address, id string, typeValue string) *BuildersTest {
+	return &BuildersTest{
+		Home: home,
+		Id:   id,
+		Type: typeValue,
+		URL:  url,
+	}
+}
+
+// WithCount sets Count and returns b.
+func (b *BuildersTest) WithCount(value int) *BuildersTest {
+	b.Count = &value
+	return b
+}
+
+// WithName sets Name and returns b.
+func (b *BuildersTest) WithName(value string) *BuildersTest {
+	b.Name = &value
+	return b
+}
+
+// WithTags sets Tags and returns b.
+func (b *BuildersTest) WithTags(value []string) *BuildersTest {
+	b.Tags = value
+	return b
+}
+
+// WithWork sets Work and returns b.
+func (b *BuildersTest) WithWork(value Address) *BuildersTest {
+	b.Work = &value
+	return b
+}
+
+// Ptr returns a pointer to v. It is useful for optional fields in struct literals.
+func Ptr[T any](v T) *T {
+	return &v
+}
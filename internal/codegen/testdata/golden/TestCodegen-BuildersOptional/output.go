@@ -0,0 +1,107 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import "encoding/json"
+
+type Address struct {
+	Street Optional[string] `json:"street,omitzero"`
+}
+
+// NewAddress returns a new Address with its required fields set.
+func NewAddress() *Address {
+	return &Address{}
+}
+
+// WithStreet sets Street and returns a.
+func (a *Address) WithStreet(value string) *Address {
+	a.Street = Optional[string]{
+		Set:   true,
+		Value: value,
+	}
+	return a
+}
+
+type BuildersTest struct {
+	URL   string             `json:"URL"`
+	Count Optional[int]      `json:"count,omitzero"`
+	Home  Address            `json:"home"`
+	Id    string             `json:"id"`
+	Name  Optional[string]   `json:"name,omitzero"`
+	Tags  Optional[[]string] `json:"tags,omitzero"`
+	Type  string             `json:"type"`
+	Work  Optional[Address]  `json:"work,omitzero"`
+}
+
+// NewBuildersTest returns a new BuildersTest with its required fields set.
+func NewBuildersTest(url string, home Address, id string, typeValue string) *BuildersTest {
+	return &BuildersTest{
+		Home: home,
+		Id:   id,
+		Type: typeValue,
+		URL:  url,
+	}
+}
+
+// WithCount sets Count and returns b.
+func (b *BuildersTest) WithCount(value int) *BuildersTest {
+	b.Count = Optional[int]{
+		Set:   true,
+		Value: value,
+	}
+	return b
+}
+
+// WithName sets Name and returns b.
+func (b *BuildersTest) WithName(value string) *BuildersTest {
+	b.Name = Optional[string]{
+		Set:   true,
+		Value: value,
+	}
+	return b
+}
+
+// WithTags sets Tags and returns b.
+func (b *BuildersTest) WithTags(value []string) *BuildersTest {
+	b.Tags = Optional[[]string]{
+		Set:   true,
+		Value: value,
+	}
+	return b
+}
+
+// WithWork sets Work and returns b.
+func (b *BuildersTest) WithWork(value Address) *BuildersTest {
+	b.Work = Optional[Address]{
+		Set:   true,
+		Value: value,
+	}
+	return b
+}
+
+// Optional is a value for a property that may be absent. Fields of this type have the omitzero
+// json option, so with Go 1.24 or later they are omitted from JSON when Set is false.
+type Optional[T any] struct {
+	Value T
+	// Set reports whether the property is present.
+	Set bool
+}
+
+// IsZero reports whether the property is absent.
+func (o Optional[T]) IsZero() bool {
+	return !o.Set
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Ptr returns a pointer to v. It is useful for optional fields in struct literals.
+func Ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,114 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Address struct {
+	Street *string `json:"street"`
+}
+
+// GetStreet returns Street, or the zero value when it is absent.
+func (a *Address) GetStreet() string {
+	if a == nil || a.Street == nil {
+		return ""
+	}
+	return *a.Street
+}
+
+type Meta struct {
+	Note *string `json:"note"`
+}
+
+type Status string
+
+type GettersTest struct {
+	Address *Address `json:"address"`
+	Count   *int     `json:"count"`
+	Enabled *bool    `json:"enabled"`
+	Home    Address  `json:"home"`
+	Id      string   `json:"id"`
+	Meta    *Meta    `json:"meta"`
+	Name    *string  `json:"name"`
+	Ratio   *float64 `json:"ratio"`
+	Status  *Status  `json:"status"`
+	Tags    []string `json:"tags"`
+}
+
+// GetAddress returns Address, or the zero value when it is absent.
+func (g *GettersTest) GetAddress() *Address {
+	if g == nil {
+		return nil
+	}
+	return g.Address
+}
+
+// GetCount returns Count, or its default when it is absent.
+func (g *GettersTest) GetCount() int {
+	if g == nil || g.Count == nil {
+		return 10
+	}
+	return *g.Count
+}
+
+// GetEnabled returns Enabled, or its default when it is absent.
+func (g *GettersTest) GetEnabled() bool {
+	if g == nil || g.Enabled == nil {
+		return true
+	}
+	return *g.Enabled
+}
+
+// GetHome returns Home, or the zero value when it is absent.
+func (g *GettersTest) GetHome() Address {
+	if g == nil {
+		return Address{}
+	}
+	return g.Home
+}
+
+// GetId returns Id, or the zero value when it is absent.
+func (g *GettersTest) GetId() string {
+	if g == nil {
+		return ""
+	}
+	return g.Id
+}
+
+// GetMeta returns Meta, or the zero value when it is absent.
+func (g *GettersTest) GetMeta() *Meta {
+	if g == nil {
+		return nil
+	}
+	return g.Meta
+}
+
+// GetName returns Name, or its default when it is absent.
+func (g *GettersTest) GetName() string {
+	if g == nil || g.Name == nil {
+		return "anonymous"
+	}
+	return *g.Name
+}
+
+// GetRatio returns Ratio, or its default when it is absent.
+func (g *GettersTest) GetRatio() float64 {
+	if g == nil || g.Ratio == nil {
+		return 0.5
+	}
+	return *g.Ratio
+}
+
+// GetStatus returns Status, or its default when it is absent.
+func (g *GettersTest) GetStatus() Status {
+	if g == nil || g.Status == nil {
+		return "active"
+	}
+	return *g.Status
+}
+
+// GetTags returns Tags, or the zero value when it is absent.
+func (g *GettersTest) GetTags() []string {
+	if g == nil {
+		return nil
+	}
+	return g.Tags
+}
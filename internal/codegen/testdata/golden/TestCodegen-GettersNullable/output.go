@@ -0,0 +1,147 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import "encoding/json"
+
+type Address struct {
+	Street Nullable[string] `json:"street,omitzero"`
+}
+
+// GetStreet returns Street, or the zero value when it is absent.
+func (a *Address) GetStreet() string {
+	if a == nil || !a.Street.Set || a.Street.Null {
+		return ""
+	}
+	return a.Street.Value
+}
+
+type Meta struct {
+	Note Nullable[string] `json:"note,omitzero"`
+}
+
+type Status string
+
+type GettersTest struct {
+	Address Nullable[Address]  `json:"address,omitzero"`
+	Count   Nullable[int]      `json:"count,omitzero"`
+	Enabled Nullable[bool]     `json:"enabled,omitzero"`
+	Home    Address            `json:"home"`
+	Id      string             `json:"id"`
+	Meta    Nullable[Meta]     `json:"meta,omitzero"`
+	Name    Nullable[string]   `json:"name,omitzero"`
+	Ratio   Nullable[float64]  `json:"ratio,omitzero"`
+	Status  Nullable[Status]   `json:"status,omitzero"`
+	Tags    Nullable[[]string] `json:"tags,omitzero"`
+}
+
+// GetAddress returns Address, or the zero value when it is absent.
+func (g *GettersTest) GetAddress() Address {
+	if g == nil || !g.Address.Set || g.Address.Null {
+		return Address{}
+	}
+	return g.Address.Value
+}
+
+// GetCount returns Count, or its default when it is absent.
+func (g *GettersTest) GetCount() int {
+	if g == nil || !g.Count.Set || g.Count.Null {
+		return 10
+	}
+	return g.Count.Value
+}
+
+// GetEnabled returns Enabled, or its default when it is absent.
+func (g *GettersTest) GetEnabled() bool {
+	if g == nil || !g.Enabled.Set || g.Enabled.Null {
+		return true
+	}
+	return g.Enabled.Value
+}
+
+// GetHome returns Home, or the zero value when it is absent.
+func (g *GettersTest) GetHome() Address {
+	if g == nil {
+		return Address{}
+	}
+	return g.Home
+}
+
+// GetId returns Id, or the zero value when it is absent.
+func (g *GettersTest) GetId() string {
+	if g == nil {
+		return ""
+	}
+	return g.Id
+}
+
+// GetMeta returns Meta, or the zero value when it is absent.
+func (g *GettersTest) GetMeta() Meta {
+	if g == nil || !g.Meta.Set || g.Meta.Null {
+		return Meta{}
+	}
+	return g.Meta.Value
+}
+
+// GetName returns Name, or its default when it is absent.
+func (g *GettersTest) GetName() string {
+	if g == nil || !g.Name.Set || g.Name.Null {
+		return "anonymous"
+	}
+	return g.Name.Value
+}
+
+// GetRatio returns Ratio, or its default when it is absent.
+func (g *GettersTest) GetRatio() float64 {
+	if g == nil || !g.Ratio.Set || g.Ratio.Null {
+		return 0.5
+	}
+	return g.Ratio.Value
+}
+
+// GetStatus returns Status, or its default when it is absent.
+func (g *GettersTest) GetStatus() Status {
+	if g == nil || !g.Status.Set || g.Status.Null {
+		return "active"
+	}
+	return g.Status.Value
+}
+
+// GetTags returns Tags, or the zero value when it is absent.
+func (g *GettersTest) GetTags() []string {
+	if g == nil || !g.Tags.Set || g.Tags.Null {
+		return nil
+	}
+	return g.Tags.Value
+}
+
+// Nullable is a value for a property that may be absent or null. Fields of this type have the
+// omitzero json option, so with Go 1.24 or later they are omitted from JSON when Set is false.
+type Nullable[T any] struct {
+	Value T
+	// Set reports whether the property is present.
+	Set bool
+	// Null reports whether the property is null.
+	Null bool
+}
+
+// IsZero reports whether the property is absent.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Set
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	*n = Nullable[T]{Set: true}
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
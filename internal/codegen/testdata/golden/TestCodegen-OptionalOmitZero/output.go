@@ -0,0 +1,76 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import "encoding/json"
+
+type Address struct {
+	Street string `json:"street,omitzero"`
+}
+
+type OptionalStrategies struct {
+	Address    Address                `json:"address,omitzero"`
+	AsNullable Nullable[string]       `json:"as_nullable,omitzero"`
+	AsOmitzero int                    `json:"as_omitzero,omitzero"`
+	AsOptional Optional[bool]         `json:"as_optional,omitzero"`
+	AsPointer  *string                `json:"as_pointer"`
+	Count      int                    `json:"count,omitzero"`
+	Id         string                 `json:"id"`
+	Labels     map[string]interface{} `json:"labels,omitzero"`
+	Name       string                 `json:"name,omitzero"`
+	Skipped    string                 `json:"skipped"`
+	Tags       []string               `json:"tags,omitzero"`
+}
+
+// Nullable is a value for a property that may be absent or null. Fields of this type have the
+// omitzero json option, so with Go 1.24 or later they are omitted from JSON when Set is false.
+type Nullable[T any] struct {
+	Value T
+	// Set reports whether the property is present.
+	Set bool
+	// Null reports whether the property is null.
+	Null bool
+}
+
+// IsZero reports whether the property is absent.
+func (n Nullable[T]) IsZero() bool {
+	return !n.Set
+}
+
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.Null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.Value)
+}
+
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	*n = Nullable[T]{Set: true}
+	if string(data) == "null" {
+		n.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &n.Value)
+}
+
+// Optional is a value for a property that may be absent. Fields of this type have the omitzero
+// json option, so with Go 1.24 or later they are omitted from JSON when Set is false.
+type Optional[T any] struct {
+	Value T
+	// Set reports whether the property is present.
+	Set bool
+}
+
+// IsZero reports whether the property is absent.
+func (o Optional[T]) IsZero() bool {
+	return !o.Set
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	return json.Unmarshal(data, &o.Value)
+}
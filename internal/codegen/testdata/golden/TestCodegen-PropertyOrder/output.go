@@ -0,0 +1,18 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Property_orderLineObject struct {
+	Description *string  `json:"description"`
+	Price       *float64 `json:"price"`
+	Quantity    *int     `json:"quantity"`
+}
+
+type Property_order struct {
+	Id     *string                   `json:"id"`
+	Kind   *string                   `json:"kind"`
+	Issued *string                   `json:"issued"`
+	Line   *Property_orderLineObject `json:"line"`
+	Number *string                   `json:"number"`
+	Total  *float64                  `json:"total"`
+}
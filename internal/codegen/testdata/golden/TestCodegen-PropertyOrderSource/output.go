@@ -0,0 +1,18 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Property_orderLineObject struct {
+	Quantity    *int     `json:"quantity"`
+	Description *string  `json:"description"`
+	Price       *float64 `json:"price"`
+}
+
+type Property_order struct {
+	Id     *string                   `json:"id"`
+	Kind   *string                   `json:"kind"`
+	Number *string                   `json:"number"`
+	Issued *string                   `json:"issued"`
+	Total  *float64                  `json:"total"`
+	Line   *Property_orderLineObject `json:"line"`
+}
@@ -0,0 +1,14 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Merchant struct {
+	Name    *string `json:"name"`
+	Country *string `json:"country"`
+}
+
+type Property_order struct {
+	Zone     *string   `json:"zone"`
+	Amount   *float64  `json:"amount"`
+	Merchant *Merchant `json:"merchant"`
+}
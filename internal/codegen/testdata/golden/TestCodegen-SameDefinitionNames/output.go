@@ -0,0 +1,22 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Address struct {
+	Street *string `json:"street"`
+}
+
+type Order struct {
+	Address *Address `json:"address"`
+	Id      *string  `json:"id"`
+}
+
+type InvoiceAddress struct {
+	Line1    *string `json:"line1"`
+	Postcode *string `json:"postcode"`
+}
+
+type Invoice struct {
+	Address *InvoiceAddress `json:"address"`
+	Id      *string         `json:"id"`
+}
@@ -0,0 +1,24 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type LineItem struct {
+	Quantity *int    `json:"quantity"`
+	Sku      *string `json:"sku"`
+}
+
+type Address struct {
+	City   *string `json:"city"`
+	Street *string `json:"street"`
+}
+
+type Customer struct {
+	Address *Address `json:"address"`
+	Name    string   `json:"name"`
+}
+
+type Order struct {
+	Customer        Customer   `json:"customer"`
+	Items           []LineItem `json:"items"`
+	ShippingAddress *Address   `json:"shipping_address"`
+}
@@ -0,0 +1,21 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import shared "example.com/shared"
+
+type LineItem struct {
+	Quantity *int    `json:"quantity"`
+	Sku      *string `json:"sku"`
+}
+
+type Address struct {
+	City   *string `json:"city"`
+	Street *string `json:"street"`
+}
+
+type Order struct {
+	Customer        shared.Client `json:"customer"`
+	Items           []LineItem    `json:"items"`
+	ShippingAddress *Address      `json:"shipping_address"`
+}
@@ -0,0 +1,16 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import shared "example.com/shared"
+
+type LineItem struct {
+	Quantity *int    `json:"quantity"`
+	Sku      *string `json:"sku"`
+}
+
+type Order struct {
+	Customer        shared.Customer `json:"customer"`
+	Items           []LineItem      `json:"items"`
+	ShippingAddress *shared.Address `json:"shipping_address"`
+}
@@ -0,0 +1,24 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type Cat struct {
+	Lives *int `json:"lives"`
+}
+
+type Dog struct {
+	Breed *string `json:"breed"`
+}
+
+type Unsupported_keywordsShippingObject struct {
+	Country *string `json:"country"`
+}
+
+type Unsupported_keywords struct {
+	Coordinates []interface{}                       `json:"coordinates"`
+	Id          *int                                `json:"id"`
+	Labels      map[string]interface{}              `json:"labels"`
+	Money       *decimal.Decimal                    `json:"money"`
+	Pet         *any                                `json:"pet"`
+	Shipping    *Unsupported_keywordsShippingObject `json:"shipping"`
+}
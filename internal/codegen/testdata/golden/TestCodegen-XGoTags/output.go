@@ -0,0 +1,9 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type XGoTagsTest struct {
+	Id          string  `db:"id" json:"id" validate:"required,uuid"`
+	Nickname    *string `json:"nickname,omitempty"`
+	NormalField *string `json:"normal_field"`
+}
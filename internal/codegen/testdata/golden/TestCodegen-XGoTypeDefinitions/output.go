@@ -0,0 +1,17 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import (
+	uuid "github.com/google/uuid"
+	"time"
+)
+
+type BackupIDs []uuid.UUID
+
+type Account struct {
+	BackupIds *BackupIDs  `json:"backup_ids"`
+	Created   *time.Time  `json:"created"`
+	History   []time.Time `json:"history"`
+	Ids       []uuid.UUID `json:"ids"`
+}
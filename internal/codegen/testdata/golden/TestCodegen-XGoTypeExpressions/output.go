@@ -0,0 +1,23 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import (
+	models "github.com/example/app/models"
+	collections "github.com/example/collections"
+	opt "github.com/example/opt"
+	googleuuid "github.com/google/uuid"
+	decimal "github.com/shopspring/decimal"
+	"time"
+)
+
+type TypeExpressions struct {
+	Checksum  *[16]byte                                   `json:"checksum"`
+	Counts    map[int]string                              `json:"counts"`
+	DeletedAt *time.Time                                  `json:"deleted_at"`
+	Ids       []googleuuid.UUID                           `json:"ids"`
+	Owner     *opt.Optional[models.User]                  `json:"owner"`
+	Pairs     *collections.Map[string, []googleuuid.UUID] `json:"pairs"`
+	Prices    map[string]decimal.Decimal                  `json:"prices"`
+	Scores    []*decimal.Decimal                          `json:"scores"`
+}
@@ -0,0 +1,16 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+import customers "example.com/gen/customers"
+
+type LineItem struct {
+	Quantity *int    `json:"quantity"`
+	Sku      *string `json:"sku"`
+}
+
+type Order struct {
+	Customer        customers.Customer `json:"customer"`
+	Items           []LineItem         `json:"items"`
+	ShippingAddress *customers.Address `json:"shipping_address"`
+}
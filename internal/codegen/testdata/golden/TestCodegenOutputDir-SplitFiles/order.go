@@ -0,0 +1,14 @@
+// Code generated by jsonschematogo. DO NOT EDIT.
+
+package gen
+
+type LineItem struct {
+	Quantity *int    `json:"quantity"`
+	Sku      *string `json:"sku"`
+}
+
+type Order struct {
+	Customer        Customer   `json:"customer"`
+	Items           []LineItem `json:"items"`
+	ShippingAddress *Address   `json:"shipping_address"`
+}
@@ -0,0 +1,181 @@
+// Package inputs expands schema file arguments that are directories or glob patterns into the
+// schema files they contain.
+package inputs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// schemaExtensions are the file extensions that are considered schema files when searching
+// directories.
+var schemaExtensions = []string{".json", ".yaml", ".yml"}
+
+// Options for Expand.
+type Options struct {
+	// Include limits the files found in directories and glob patterns to those matching at least one
+	// of these patterns.
+	Include []string
+	// Exclude removes files found in directories and glob patterns that match any of these patterns.
+	Exclude []string
+}
+
+// Expand replaces directories and glob patterns in args with the schema files they contain.
+// Directories are searched recursively for .json, .yaml and .yml files. Glob patterns use
+// path.Match syntax with the addition of "**", which matches any number of directories. Other args,
+// including "-" and URLs, are returned unchanged. The files for each arg are sorted, and files that
+// appear more than once are only returned the first time.
+func Expand(args []string, opts *Options) ([]string, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	for _, pattern := range slices.Concat(opts.Include, opts.Exclude) {
+		_, err := path.Match(pattern, "")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	var files []string
+	seen := map[string]bool{}
+	add := func(file string) {
+		if !seen[file] {
+			seen[file] = true
+			files = append(files, file)
+		}
+	}
+	for _, arg := range args {
+		found, err := expandArg(arg, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range found {
+			add(file)
+		}
+	}
+	return files, nil
+}
+
+func expandArg(arg string, opts *Options) ([]string, error) {
+	if arg == "-" || strings.Contains(arg, "://") {
+		return []string{arg}, nil
+	}
+	if hasMeta(arg) {
+		return expandGlob(arg, opts)
+	}
+	info, err := os.Stat(arg)
+	if err != nil || !info.IsDir() {
+		// Missing files are reported when they are loaded.
+		return []string{arg}, nil
+	}
+	return walk(arg, opts, func(string) bool { return true })
+}
+
+func expandGlob(pattern string, opts *Options) ([]string, error) {
+	slashPattern := filepath.ToSlash(pattern)
+	_, err := path.Match(slashPattern, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	segments := strings.Split(slashPattern, "/")
+	i := slices.IndexFunc(segments, hasMeta)
+	root := strings.Join(segments[:i], "/")
+	if root == "" && i > 0 {
+		// The pattern is absolute
+		root = "/"
+	}
+	walkRoot := filepath.FromSlash(root)
+	if walkRoot == "" {
+		walkRoot = "."
+	}
+	var files []string
+	info, err := os.Stat(walkRoot)
+	if err == nil && info.IsDir() {
+		files, err = walk(walkRoot, opts, func(file string) bool {
+			return Match(slashPattern, filepath.ToSlash(file))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no schema files match %q", pattern)
+	}
+	return files, nil
+}
+
+// walk returns the sorted schema files under root that match and pass the include and exclude
+// filters.
+func walk(root string, opts *Options, match func(file string) bool) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !slices.Contains(schemaExtensions, strings.ToLower(filepath.Ext(file))) {
+			return nil
+		}
+		if match(file) && opts.filter(file) {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (o *Options) filter(file string) bool {
+	file = filepath.ToSlash(file)
+	matchAny := func(patterns []string) bool {
+		return slices.ContainsFunc(patterns, func(pattern string) bool {
+			return Match(pattern, file)
+		})
+	}
+	if len(o.Include) > 0 && !matchAny(o.Include) {
+		return false
+	}
+	return !matchAny(o.Exclude)
+}
+
+// Match reports whether a slash separated file path matches pattern. Pattern segments are matched
+// with path.Match, and a "**" segment matches zero or more path segments. A leading "./" is ignored in
+// both the pattern and the name.
+func Match(pattern, name string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	name = strings.TrimPrefix(name, "./")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+	return len(name) == 0
+}
+
+func hasMeta(s string) bool {
+	return strings.ContainsAny(s, "*?[")
+}
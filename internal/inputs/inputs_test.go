@@ -0,0 +1,125 @@
+package inputs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/inputs"
+)
+
+func TestExpand(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, file := range []string{
+		"schemas/b.yaml",
+		"schemas/a.json",
+		"schemas/readme.md",
+		"schemas/nested/c.yml",
+		"schemas/nested/deeper/d.yaml",
+		"schemas/internal/e.yaml",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o700))
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0o600))
+	}
+
+	for _, test := range []struct {
+		name string
+		args []string
+		opts *inputs.Options
+		want []string
+	}{
+		{
+			name: "directory",
+			args: []string{"schemas"},
+			want: []string{
+				"schemas/a.json",
+				"schemas/b.yaml",
+				"schemas/internal/e.yaml",
+				"schemas/nested/c.yml",
+				"schemas/nested/deeper/d.yaml",
+			},
+		},
+		{
+			name: "glob",
+			args: []string{"schemas/*.yaml"},
+			want: []string{"schemas/b.yaml"},
+		},
+		{
+			name: "double star",
+			args: []string{"schemas/**/*.yaml"},
+			want: []string{
+				"schemas/b.yaml",
+				"schemas/internal/e.yaml",
+				"schemas/nested/deeper/d.yaml",
+			},
+		},
+		{
+			name: "exclude",
+			args: []string{"schemas"},
+			opts: &inputs.Options{Exclude: []string{"**/internal/**", "**/*.json"}},
+			want: []string{
+				"schemas/b.yaml",
+				"schemas/nested/c.yml",
+				"schemas/nested/deeper/d.yaml",
+			},
+		},
+		{
+			name: "include",
+			args: []string{"schemas"},
+			opts: &inputs.Options{Include: []string{"schemas/nested/**"}},
+			want: []string{
+				"schemas/nested/c.yml",
+				"schemas/nested/deeper/d.yaml",
+			},
+		},
+		{
+			name: "keeps order and removes duplicates",
+			args: []string{"schemas/nested/c.yml", "-", "https://example.com/x.json", "schemas/nested"},
+			want: []string{
+				"schemas/nested/c.yml",
+				"-",
+				"https://example.com/x.json",
+				"schemas/nested/deeper/d.yaml",
+			},
+		},
+		{
+			name: "missing file",
+			args: []string{"missing.yaml"},
+			want: []string{"missing.yaml"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := inputs.Expand(test.args, test.opts)
+			require.NoError(t, err)
+			for i := range got {
+				got[i] = filepath.ToSlash(got[i])
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("glob without matches", func(t *testing.T) {
+		_, err := inputs.Expand([]string{"schemas/*.txt"}, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestMatch(t *testing.T) {
+	for _, test := range []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "**", name: "a/b/c.yaml", want: true},
+		{pattern: "a/**/c.yaml", name: "a/c.yaml", want: true},
+		{pattern: "a/**/c.yaml", name: "a/b/b/c.yaml", want: true},
+		{pattern: "a/**/c.yaml", name: "b/c.yaml", want: false},
+		{pattern: "./a/*.yaml", name: "a/c.yaml", want: true},
+		{pattern: "a/*.yaml", name: "a/b/c.yaml", want: false},
+	} {
+		assert.Equal(t, test.want, inputs.Match(test.pattern, test.name), "%s %s", test.pattern, test.name)
+	}
+}
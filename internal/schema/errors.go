@@ -0,0 +1,267 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+// Error is an error about a value in a schema document.
+type Error struct {
+	// Location is the URL of the document with a JSON pointer fragment.
+	Location string
+	// Position is the position of the value in the document. It is zero when unknown.
+	Position schemaloader.Position
+	// Snippet is the lines around Position with the value's line marked. It is empty when the
+	// position is unknown.
+	Snippet string
+	Err     error
+}
+
+// Error returns a message like "schemas/order.yaml:12:5: #/properties/id: message" followed by the
+// snippet.
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Path())
+	if e.Position.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", e.Position.Line, e.Position.Column)
+	}
+	fmt.Fprintf(&b, ": #%s: %v", e.Pointer(), e.Err)
+	if e.Snippet != "" {
+		b.WriteString("\n")
+		b.WriteString(e.Snippet)
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Path returns the document's file path relative to the working directory, or its URL when it
+// isn't a local file.
+func (e *Error) Path() string {
+	document, _, _ := strings.Cut(e.Location, "#")
+	return displayPath(document)
+}
+
+// Pointer returns the JSON pointer of the value in the document.
+func (e *Error) Pointer() string {
+	_, pointer, _ := strings.Cut(e.Location, "#")
+	return pointer
+}
+
+// displayPath returns the path of a file URL relative to the working directory when it is below it.
+// Other URLs are returned unchanged.
+func displayPath(document string) string {
+	u, err := url.Parse(document)
+	if err != nil || u.Scheme != "file" {
+		return document
+	}
+	filename := filepath.FromSlash(u.Path)
+	wd, err := os.Getwd()
+	if err != nil {
+		return filename
+	}
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filename
+	}
+	return rel
+}
+
+// source is the content and outline of a loaded document.
+type source struct {
+	outline *schemaloader.Outline
+	lines   []string
+	// value is the decoded document.
+	value any
+}
+
+// sources holds the loaded documents keyed by URL.
+type sources map[string]*source
+
+func (s sources) add(document string, data []byte) {
+	// Outlines are only used for ordering and error messages, so documents that can't be
+	// outlined fall back to sorting by name and errors without positions.
+	outline, err := schemaloader.OutlineDocument(data)
+	if err != nil {
+		return
+	}
+	s[document] = &source{
+		outline: outline,
+		lines:   strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"),
+	}
+}
+
+// keys returns the declared order of the keys of the object at location, which is a document URL
+// with a JSON pointer fragment.
+func (s sources) keys(location string) []string {
+	document, pointer, _ := strings.Cut(location, "#")
+	src := s[document]
+	if src == nil {
+		return nil
+	}
+	return src.outline.Keys[pointer]
+}
+
+// lookup returns the decoded value at location.
+func (s sources) lookup(location string) (any, bool) {
+	document, pointer, _ := strings.Cut(location, "#")
+	src := s[document]
+	if src == nil || src.value == nil {
+		return nil, false
+	}
+	value := src.value
+	if pointer == "" {
+		return value, true
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch v := value.(type) {
+		case map[string]any:
+			var ok bool
+			value, ok = v[token]
+			if !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// errorAt returns an *Error for location. The position is that of the closest ancestor of location
+// with a known position.
+func (s sources) errorAt(location string, err error) *Error {
+	e := &Error{Location: location, Err: err}
+	document, pointer, _ := strings.Cut(location, "#")
+	src := s[document]
+	if src == nil {
+		return e
+	}
+	for {
+		position, ok := src.outline.Positions[pointer]
+		if ok {
+			e.Position = position
+			e.Snippet = snippet(src.lines, position)
+			return e
+		}
+		i := strings.LastIndex(pointer, "/")
+		if i < 0 {
+			return e
+		}
+		pointer = pointer[:i]
+	}
+}
+
+// snippet returns the line at position with the lines around it and a caret under the column.
+func snippet(lines []string, position schemaloader.Position) string {
+	if position.Line < 1 || position.Line > len(lines) {
+		return ""
+	}
+	first := max(position.Line-2, 1)
+	last := min(position.Line+1, len(lines))
+	width := len(fmt.Sprint(last))
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		line := strings.TrimRight(lines[n-1], "\r")
+		marker := " "
+		if n == position.Line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d |", marker, width, n)
+		if line != "" {
+			b.WriteString(" " + line)
+		}
+		b.WriteString("\n")
+		if n == position.Line {
+			// Keep tabs so that the caret lines up with the value.
+			prefix := line
+			if byteOffset := runeOffset(line, position.Column-1); byteOffset >= 0 {
+				prefix = line[:byteOffset]
+			}
+			indent := strings.Map(func(r rune) rune {
+				if r == '\t' {
+					return r
+				}
+				return ' '
+			}, prefix)
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", indent)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// runeOffset returns the byte offset of the n-th rune in s or -1 when s is shorter.
+func runeOffset(s string, n int) int {
+	offset := 0
+	for i := 0; i < n; i++ {
+		if offset >= len(s) {
+			return -1
+		}
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// locateCompileError adds the location of the offending value to errors from compiling a schema.
+// Errors without a location are returned unchanged.
+func (s sources) locateCompileError(err error) error {
+	var validationErr *jsonschema.SchemaValidationError
+	if errors.As(err, &validationErr) {
+		location := validationErr.URL
+		if !strings.Contains(location, "#") {
+			location += "#"
+		}
+		var cause *jsonschema.ValidationError
+		if errors.As(validationErr.Err, &cause) {
+			for _, token := range deepestInstanceLocation(cause) {
+				location += "/" + escapeJSONPointerToken(token)
+			}
+		}
+		return s.errorAt(location, err)
+	}
+	var regexErr *jsonschema.InvalidRegexError
+	if errors.As(err, &regexErr) {
+		return s.errorAt(regexErr.URL, err)
+	}
+	var idErr *jsonschema.ParseIDError
+	if errors.As(err, &idErr) {
+		return s.errorAt(idErr.URL, err)
+	}
+	var anchorErr *jsonschema.ParseAnchorError
+	if errors.As(err, &anchorErr) {
+		return s.errorAt(anchorErr.URL, err)
+	}
+	return err
+}
+
+// deepestInstanceLocation returns the most specific instance location of a validation error and
+// its causes.
+func deepestInstanceLocation(err *jsonschema.ValidationError) []string {
+	deepest := err.InstanceLocation
+	for _, cause := range err.Causes {
+		location := deepestInstanceLocation(cause)
+		if len(location) > len(deepest) {
+			deepest = location
+		}
+	}
+	return deepest
+}
@@ -2,35 +2,45 @@ package schema
 
 import (
 	"fmt"
-	"net/url"
-	"path/filepath"
+	"maps"
+	"slices"
 	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/willabides/jsonschematogo/internal/schemaloader"
 )
 
-// LoadSchema loads a JSON or YAML schema file and parses it into a *Schema model.
-func LoadSchema(filename string) (*Schema, error) {
+// LoadSchema loads a JSON or YAML schema file or URL and parses it into a *Schema model.
+func LoadSchema(filename string, opts *schemaloader.Options) (*Schema, error) {
 	if filename == "" {
 		return nil, fmt.Errorf("filename cannot be empty")
 	}
-	u, err := url.Parse(filename)
+	fileURL, err := schemaloader.ToURL(filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse URL: %w", err)
-	}
-	absPath, err := filepath.Abs(u.Path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+		return nil, err
 	}
-	fileURL := "file://" + absPath
 
 	schemaMap := map[string]any{}
+	srcs := sources{}
+	loaderOpts := schemaloader.Options{}
+	if opts != nil {
+		loaderOpts = *opts
+	}
+	onRead := loaderOpts.OnRead
+	loaderOpts.OnRead = func(url string, data []byte) {
+		srcs.add(url, data)
+		if onRead != nil {
+			onRead(url, data)
+		}
+	}
 	loader, err := schemaloader.New(
 		func(url string, schema any) {
 			schemaMap[url] = schema
+			if src := srcs[url]; src != nil {
+				src.value = schema
+			}
 		},
-		&schemaloader.Options{},
+		&loaderOpts,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create schema loader: %w", err)
@@ -40,7 +50,7 @@ func LoadSchema(filename string) (*Schema, error) {
 
 	compiled, err := compiler.Compile(fileURL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to compile schema: %w", err)
+		return nil, fmt.Errorf("failed to compile schema: %w", srcs.locateCompileError(err))
 	}
 
 	rawSchema, ok := schemaMap[fileURL]
@@ -52,20 +62,22 @@ func LoadSchema(filename string) (*Schema, error) {
 		return nil, fmt.Errorf("unexpected schema type: %T", rawSchema)
 	}
 
-	definitions, err := compileDefinitions(compiler, fileURL, rawMap)
+	definitions, err := compileDefinitions(compiler, fileURL, rawMap, srcs)
 	if err != nil {
 		return nil, err
 	}
 
-	schema := fromJSONSchema(compiled, rawMap)
+	schema := fromJSONSchema(compiled, rawMap, srcs)
 	schema.definitions = definitions
+	schema.documents = slices.Sorted(maps.Keys(schemaMap))
 	return schema, nil
 }
 
-func fromJSONSchema(compiled *jsonschema.Schema, rawMap map[string]any) *Schema {
+func fromJSONSchema(compiled *jsonschema.Schema, rawMap map[string]any, srcs sources) *Schema {
 	return &Schema{
-		schema: compiled,
-		rawMap: rawMap,
+		schema:  compiled,
+		rawMap:  rawMap,
+		sources: srcs,
 	}
 }
 
@@ -73,10 +85,11 @@ func compileDefinitions(
 	compiler *jsonschema.Compiler,
 	fileURL string,
 	rawMap map[string]any,
+	srcs sources,
 ) ([]NamedSchema, error) {
 	definitions := []NamedSchema{}
 	for _, keyword := range []string{"$defs", "definitions"} {
-		keywordDefinitions, err := compileKeywordDefinitions(compiler, fileURL, rawMap, keyword)
+		keywordDefinitions, err := compileKeywordDefinitions(compiler, fileURL, rawMap, srcs, keyword)
 		if err != nil {
 			return nil, err
 		}
@@ -89,6 +102,7 @@ func compileKeywordDefinitions(
 	compiler *jsonschema.Compiler,
 	fileURL string,
 	rawMap map[string]any,
+	srcs sources,
 	keyword string,
 ) ([]NamedSchema, error) {
 	rawDefinitions, definitionsExist := rawMap[keyword].(map[string]any)
@@ -111,12 +125,12 @@ func compileKeywordDefinitions(
 		location := fileURL + ref
 		compiled, err := compiler.Compile(location)
 		if err != nil {
-			return nil, fmt.Errorf("compile definition %q: %w", name, err)
+			return nil, fmt.Errorf("compile definition %q: %w", name, srcs.locateCompileError(err))
 		}
 		definitions = append(definitions, NamedSchema{
 			Name:   name,
 			Ref:    ref,
-			Schema: fromJSONSchema(compiled, rawDefinitionMap),
+			Schema: fromJSONSchema(compiled, rawDefinitionMap, srcs),
 		})
 	}
 	return definitions, nil
@@ -128,7 +142,7 @@ func escapeJSONPointerToken(token string) string {
 }
 
 // LoadAllSchemas loads all entry schemas and recursively loads all referenced schemas.
-func LoadAllSchemas(entryFiles []string) (map[string]*Schema, error) {
+func LoadAllSchemas(entryFiles []string, opts *schemaloader.Options) (map[string]*Schema, error) {
 	if len(entryFiles) == 0 {
 		return nil, fmt.Errorf("no schema files provided")
 	}
@@ -136,7 +150,7 @@ func LoadAllSchemas(entryFiles []string) (map[string]*Schema, error) {
 	schemas := make(map[string]*Schema)
 	// Load all entry point schemas
 	for _, file := range entryFiles {
-		sch, err := LoadSchema(file)
+		sch, err := LoadSchema(file, opts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load schema %s: %w", file, err)
 		}
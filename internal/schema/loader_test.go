@@ -1,14 +1,20 @@
 package schema
 
 import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
 )
 
 func TestLoadSchema(t *testing.T) {
-	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml")
+	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml", nil)
 	require.NoError(t, err)
 	assert.Equal(t, "object", schema.Type())
 
@@ -38,3 +44,71 @@ func TestLoadSchema(t *testing.T) {
 	assert.NotNil(t, items)
 	assert.Equal(t, "person.yaml", items.Ref())
 }
+
+func TestLoadSchema_Documents(t *testing.T) {
+	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml", nil)
+	require.NoError(t, err)
+	documents := schema.Documents()
+	require.Len(t, documents, 2)
+	assert.True(t, strings.HasSuffix(documents[0], "/company/company.yaml"), documents[0])
+	assert.True(t, strings.HasSuffix(documents[1], "/company/person.yaml"), documents[1])
+}
+
+func TestLoadSchema_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"company.yaml": {Data: []byte(`
+title: Company
+type: object
+properties:
+  ceo:
+    $ref: people/person.yaml
+`)},
+		"people/person.yaml": {Data: []byte(`
+title: Person
+type: object
+properties:
+  name:
+    type: string
+`)},
+	}
+	schema, err := LoadSchema("embed:///company.yaml", &schemaloader.Options{
+		FS: map[string]fs.FS{"embed:///": fsys},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"embed:///company.yaml", "embed:///people/person.yaml"}, schema.Documents())
+	ceo := schema.Properties()["ceo"].RefSchema()
+	require.NotNil(t, ceo)
+	assert.Equal(t, "embed:///people/person.yaml#", ceo.Location())
+
+	_, err = LoadSchema("embed:///missing.yaml", &schemaloader.Options{
+		FS: map[string]fs.FS{"embed:///": fsys},
+	})
+	require.Error(t, err)
+}
+
+func TestLoadSchema_Sources(t *testing.T) {
+	schema, err := LoadSchema("mem://schemas/person.json", &schemaloader.Options{
+		Sources: map[string][]byte{
+			"mem://schemas/person.json": []byte(`{"type": "object", "properties": {"name": {"type": "string"}}}`),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "object", schema.Type())
+	assert.Equal(t, []string{"mem://schemas/person.json"}, schema.Documents())
+}
+
+func TestLoadSchema_ErrorPosition(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "entry.json")
+	require.NoError(t, os.WriteFile(filename, []byte("{\n  \"properties\": {\n    \"a\": {\"pattern\": \"[\"}\n  }\n}\n"), 0o600))
+	_, err := LoadSchema(filename, nil)
+	require.Error(t, err)
+	var schemaErr *Error
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, schemaloader.Position{Line: 3, Column: 11}, schemaErr.Position)
+	assert.True(t, strings.HasSuffix(schemaErr.Location, "/entry.json#/properties/a/pattern"), schemaErr.Location)
+	assert.Equal(t, `  1 | {
+  2 |   "properties": {
+> 3 |     "a": {"pattern": "["}
+    |           ^
+  4 |   }`, schemaErr.Snippet)
+}
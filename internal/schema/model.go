@@ -3,9 +3,14 @@ package schema
 import (
 	"cmp"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
 	"iter"
 	"maps"
+	"reflect"
 	"slices"
+	"strings"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
@@ -15,6 +20,8 @@ type Schema struct {
 	schema      *jsonschema.Schema
 	rawMap      map[string]any
 	definitions []NamedSchema
+	documents   []string
+	sources     sources
 }
 
 // NamedSchema is a reusable schema declared in $defs or definitions.
@@ -35,6 +42,11 @@ func (s *Schema) Type() string {
 	return ""
 }
 
+// Description returns the schema's description keyword.
+func (s *Schema) Description() string {
+	return s.schema.Description
+}
+
 // Ref returns the schema reference.
 func (s *Schema) Ref() string {
 	// Try raw map first to preserve original references
@@ -53,7 +65,8 @@ func (s *Schema) Ref() string {
 func (s *Schema) RefSchema() *Schema {
 	if s.schema.Ref != nil {
 		schema := Schema{
-			schema: s.schema.Ref,
+			schema:  s.schema.Ref,
+			sources: s.sources,
 		}
 		getMapValue(s.rawMap, "$ref", &schema.rawMap)
 		return &schema
@@ -76,8 +89,9 @@ func (s *Schema) Items() *Schema {
 			}
 		}
 		return &Schema{
-			schema: s.schema.Items2020,
-			rawMap: itemsRawMap,
+			schema:  s.schema.Items2020,
+			rawMap:  itemsRawMap,
+			sources: s.sources,
 		}
 	}
 
@@ -94,8 +108,9 @@ func (s *Schema) Items() *Schema {
 			}
 		}
 		return &Schema{
-			schema: items,
-			rawMap: itemsRawMap,
+			schema:  items,
+			rawMap:  itemsRawMap,
+			sources: s.sources,
 		}
 	case []*jsonschema.Schema:
 		if len(items) > 0 {
@@ -106,28 +121,73 @@ func (s *Schema) Items() *Schema {
 				}
 			}
 			return &Schema{
-				schema: items[0],
-				rawMap: itemsRawMap,
+				schema:  items[0],
+				rawMap:  itemsRawMap,
+				sources: s.sources,
 			}
 		}
 	}
 	return nil
 }
 
-func orderedMap[K cmp.Ordered, V any](m map[K]V) iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		keys := slices.Collect(maps.Keys(m))
-		slices.Sort(keys)
-		for _, key := range keys {
-			if !yield(key, m[key]) {
-				return
-			}
+// OrderedProperties returns the properties sorted by name. Properties with an x-order extension come
+// first, sorted by it.
+func (s *Schema) OrderedProperties() iter.Seq2[string, *Schema] {
+	props := s.Properties()
+	return s.propertiesInOrder(props, slices.Sorted(maps.Keys(props)))
+}
+
+// SourceOrderedProperties returns the properties in the order they are declared in the schema
+// document. Properties with an x-order extension come first, sorted by it.
+func (s *Schema) SourceOrderedProperties() iter.Seq2[string, *Schema] {
+	props := s.Properties()
+	var names []string
+	for _, name := range s.sources.keys(s.Location() + "/properties") {
+		if _, ok := props[name]; ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	// Fall back to sorting properties whose order is unknown.
+	for _, name := range slices.Sorted(maps.Keys(props)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
 		}
 	}
+	return s.propertiesInOrder(props, names)
 }
 
-func (s *Schema) OrderedProperties() iter.Seq2[string, *Schema] {
-	return orderedMap(s.Properties())
+// propertiesInOrder moves the properties with x-order in front of names, keeping the order of names
+// for properties with the same or no x-order.
+func (s *Schema) propertiesInOrder(props map[string]*Schema, names []string) iter.Seq2[string, *Schema] {
+	xOrder := func(name string) (int, bool) {
+		var order json.Number
+		if getMapValue(props[name].rawMap, "x-order", &order) {
+			n, err := order.Int64()
+			return int(n), err == nil
+		}
+		var n int
+		return n, getMapValue(props[name].rawMap, "x-order", &n)
+	}
+	slices.SortStableFunc(names, func(a, b string) int {
+		aOrder, aOK := xOrder(a)
+		bOrder, bOK := xOrder(b)
+		switch {
+		case aOK && bOK:
+			return cmp.Compare(aOrder, bOrder)
+		case aOK:
+			return -1
+		case bOK:
+			return 1
+		}
+		return 0
+	})
+	return func(yield func(string, *Schema) bool) {
+		for _, name := range names {
+			if !yield(name, props[name]) {
+				return
+			}
+		}
+	}
 }
 
 // OrderedDefinitions returns the schema definitions sorted by name and reference.
@@ -143,6 +203,12 @@ func (s *Schema) OrderedDefinitions() iter.Seq[NamedSchema] {
 	return slices.Values(definitions)
 }
 
+// Documents returns the sorted URLs of every schema document that was loaded to compile an entry
+// schema. It is empty for schemas that aren't entry schemas.
+func (s *Schema) Documents() []string {
+	return s.documents
+}
+
 // Properties returns the schema properties.
 func (s *Schema) Properties() map[string]*Schema {
 	if s.schema.Properties == nil {
@@ -165,8 +231,9 @@ func (s *Schema) Properties() map[string]*Schema {
 			}
 		}
 		props[propName] = &Schema{
-			schema: propSchema,
-			rawMap: propRawMap,
+			schema:  propSchema,
+			rawMap:  propRawMap,
+			sources: s.sources,
 		}
 	}
 	return props
@@ -187,41 +254,134 @@ type GoTypeImport struct {
 	Name string `json:"name"`
 }
 
+// GoTypeImports are the packages that x-go-type refers to. x-go-type-import is either one import
+// or a list of them.
+type GoTypeImports []GoTypeImport
+
+func (imports *GoTypeImports) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '{' {
+		var imp GoTypeImport
+		err := json.Unmarshal(data, &imp)
+		if err != nil {
+			return err
+		}
+		*imports = GoTypeImports{imp}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]GoTypeImport)(imports))
+}
+
 type Extensions struct {
 	GoType       *string       `json:"x-go-type"`
-	GoTypeImport *GoTypeImport `json:"x-go-type-import"`
+	GoTypeImport GoTypeImports `json:"x-go-type-import"`
 	GoName       *string       `json:"x-go-name"`
 	GoTypeName   *string       `json:"x-go-type-name"`
+	// GoTags are struct tags to add to the field generated for a property, keyed by tag name.
+	GoTags map[string]string `json:"x-go-tags"`
+	// GoGetters generates getter methods for the fields of the struct generated from an object
+	// schema.
+	GoGetters *bool `json:"x-go-getters"`
+	// GoOptional is how the field for a property that isn't required is represented.
+	GoOptional *string `json:"x-go-optional"`
+	// GoTypeSkipOptionalPointer uses the type itself for a property that isn't required. It is
+	// compatible with oapi-codegen.
+	GoTypeSkipOptionalPointer *bool `json:"x-go-type-skip-optional-pointer"`
 }
 
+// Extensions returns the schema's x-go-* extensions. Errors are *Error values that point at the
+// offending extension.
 func (s *Schema) Extensions() (*Extensions, error) {
-	b, err := json.Marshal(s.rawMap)
+	b, err := json.Marshal(s.keywords())
 	if err != nil {
-		return nil, err
+		return nil, s.Errorf("%w", err)
 	}
 	var ext Extensions
 	err = json.Unmarshal(b, &ext)
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		pointer := ""
+		for _, token := range strings.Split(typeErr.Field, ".") {
+			pointer += "/" + escapeJSONPointerToken(token)
+		}
+		return nil, s.sources.errorAt(
+			s.Location()+pointer,
+			fmt.Errorf("invalid %s: got %s, want %s", typeErr.Field, typeErr.Value, jsonTypeName(typeErr.Type)),
+		)
+	}
 	if err != nil {
-		return nil, err
+		return nil, s.Errorf("%w", err)
+	}
+	for _, identifier := range []struct {
+		keyword string
+		value   *string
+	}{
+		{keyword: "x-go-name", value: ext.GoName},
+		{keyword: "x-go-type-name", value: ext.GoTypeName},
+	} {
+		if identifier.value != nil && !token.IsIdentifier(*identifier.value) {
+			return nil, s.sources.errorAt(
+				s.Location()+"/"+identifier.keyword,
+				fmt.Errorf("invalid %s: %q is not a Go identifier", identifier.keyword, *identifier.value),
+			)
+		}
 	}
 	return &ext, nil
 }
 
-// GetImportExtension retrieves import information from x-go-type-import extension.
-// Returns path and name if the extension exists and is properly formatted.
-func (s *Schema) GetImportExtension() (path, name string, exists bool) {
-	var importMap map[string]any
-	if !getMapValue(s.rawMap, "x-go-type-import", &importMap) {
-		return "", "", false
+// jsonTypeName returns the JSON name for the kind of value a Go type is decoded from.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonTypeName(t.Elem())
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	default:
+		return "number"
 	}
-	if !getMapValue(importMap, "path", &path) {
-		return "", "", false
+}
+
+// Keyword returns the value of a keyword in the schema document and whether the schema has it.
+func (s *Schema) Keyword(name string) (any, bool) {
+	value, ok := s.keywords()[name]
+	return value, ok
+}
+
+// ExtensionKeywords returns the schema's keywords that start with "x-".
+func (s *Schema) ExtensionKeywords() map[string]any {
+	extensions := map[string]any{}
+	for name, value := range s.keywords() {
+		if strings.HasPrefix(name, "x-") {
+			extensions[name] = value
+		}
 	}
-	if !getMapValue(importMap, "name", &name) {
-		return "", "", false
+	return extensions
+}
+
+// keywords returns the schema's object from the schema document.
+func (s *Schema) keywords() map[string]any {
+	if s.rawMap != nil {
+		return s.rawMap
 	}
+	// Schemas reached through $ref don't keep their document value, so look it up by location.
+	value, _ := s.sources.lookup(s.Location())
+	rawMap, _ := value.(map[string]any)
+	return rawMap
+}
+
+// KeywordError returns an *Error that points at a keyword of the schema.
+func (s *Schema) KeywordError(name string, err error) *Error {
+	return s.sources.errorAt(s.Location()+"/"+escapeJSONPointerToken(name), err)
+}
 
-	return path, name, true
+// Errorf returns an *Error that points at the schema.
+func (s *Schema) Errorf(format string, args ...any) error {
+	return s.sources.errorAt(s.Location(), fmt.Errorf(format, args...))
 }
 
 // HasProperties returns true if the schema has properties (is an object with properties).
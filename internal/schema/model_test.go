@@ -1,6 +1,10 @@
 package schema
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -8,26 +12,70 @@ import (
 )
 
 func TestSchema_IsPrimitive(t *testing.T) {
-	schema, err := LoadSchema("../codegen/testdata/schemas/primitives.yaml")
+	schema, err := LoadSchema("../codegen/testdata/schemas/primitives.yaml", nil)
 	require.NoError(t, err)
 	assert.NotEmpty(t, schema.Type())
 }
 
 func TestSchema_IsObject(t *testing.T) {
-	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml")
+	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml", nil)
 	require.NoError(t, err)
 	assert.True(t, schema.IsObject())
 }
 
 func TestSchema_HasProperties(t *testing.T) {
-	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml")
+	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml", nil)
 	require.NoError(t, err)
 	assert.True(t, schema.HasProperties())
 }
 
 func TestSchema_IsPropertyRequired(t *testing.T) {
-	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml")
+	schema, err := LoadSchema("../codegen/testdata/schemas/company/company.yaml", nil)
 	require.NoError(t, err)
 	assert.True(t, schema.IsPropertyRequired("name"))
 	assert.False(t, schema.IsPropertyRequired("email"))
 }
+
+func TestSchema_Extensions_GoIdentifiers(t *testing.T) {
+	for _, test := range []struct {
+		keyword string
+		value   string
+		wantErr string
+	}{
+		{keyword: "x-go-name", value: "UserID"},
+		{keyword: "x-go-name", value: "User ID", wantErr: `invalid x-go-name: "User ID" is not a Go identifier`},
+		{keyword: "x-go-type-name", value: "Owner"},
+		{keyword: "x-go-type-name", value: "1Owner", wantErr: `invalid x-go-type-name: "1Owner" is not a Go identifier`},
+	} {
+		t.Run(test.keyword+"="+test.value, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "schema.json")
+			data := fmt.Sprintf(`{"type": "object", %q: %q}`, test.keyword, test.value)
+			require.NoError(t, os.WriteFile(filename, []byte(data), 0o600))
+			schema, err := LoadSchema(filename, nil)
+			require.NoError(t, err)
+			_, err = schema.Extensions()
+			if test.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			var schemaErr *Error
+			require.ErrorAs(t, err, &schemaErr)
+			assert.True(t, strings.HasSuffix(schemaErr.Location, "#/"+test.keyword), schemaErr.Location)
+			assert.EqualError(t, schemaErr.Err, test.wantErr)
+		})
+	}
+}
+
+func TestSchema_Extensions_RefSchema(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "schema.json")
+	data := `{"type": "object", "properties": {"id": {"$ref": "#/$defs/id"}}, "$defs": {"id": {"type": "string", "x-go-type": "uuid.UUID"}}}`
+	require.NoError(t, os.WriteFile(filename, []byte(data), 0o600))
+	schema, err := LoadSchema(filename, nil)
+	require.NoError(t, err)
+	refSchema := schema.Properties()["id"].RefSchema()
+	require.NotNil(t, refSchema)
+	ext, err := refSchema.Extensions()
+	require.NoError(t, err)
+	require.NotNil(t, ext.GoType)
+	assert.Equal(t, "uuid.UUID", *ext.GoType)
+}
@@ -0,0 +1,337 @@
+// Package schemabundle combines a schema and every document it references into a single
+// self-contained schema.
+package schemabundle
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/willabides/jsonschematogo/internal/schema"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+// Bundle loads an entry schema and inlines every document it references into the entry's $defs
+// (definitions for draft-04 to draft-07 schemas). References are rewritten to JSON pointers into the
+// bundle, and $id and $anchor keywords below the root are removed so that the pointers resolve
+// against the bundle. References to documents that weren't loaded, like meta-schemas, are left
+// unchanged.
+func Bundle(entry string, opts *schemaloader.Options) (any, error) {
+	entryURL, err := schemaloader.ToURL(entry)
+	if err != nil {
+		return nil, err
+	}
+	loaderOpts := schemaloader.Options{}
+	if opts != nil {
+		loaderOpts = *opts
+	}
+	raw := map[string][]byte{}
+	onRead := loaderOpts.OnRead
+	loaderOpts.OnRead = func(uri string, data []byte) {
+		raw[uri] = data
+		if onRead != nil {
+			onRead(uri, data)
+		}
+	}
+	// Loading compiles the schemas, so references that don't resolve fail here.
+	_, err = schema.LoadSchema(entry, &loaderOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &bundler{
+		entry:     entryURL,
+		documents: map[string]*document{},
+		resources: map[string]location{},
+		names:     map[string]string{},
+	}
+	for _, u := range slices.Sorted(maps.Keys(raw)) {
+		root, err := schemaloader.Decode(raw[u])
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", u, err)
+		}
+		doc := &document{url: u, root: root, idKeyword: idKeyword(root)}
+		b.documents[u] = doc
+		b.index(doc)
+	}
+	entryDoc, ok := b.documents[entryURL]
+	if !ok {
+		return nil, fmt.Errorf("schema %s wasn't loaded", entryURL)
+	}
+	rootMap, ok := entryDoc.root.(map[string]any)
+	if !ok {
+		// A boolean schema has no references.
+		return entryDoc.root, nil
+	}
+	defsKeyword := definitionsKeyword(rootMap)
+	b.used = map[string]bool{}
+	if defs, ok := rootMap[defsKeyword].(map[string]any); ok {
+		for name := range defs {
+			b.used[name] = true
+		}
+	}
+	b.defsKeyword = defsKeyword
+
+	err = b.rewrite(entryDoc)
+	if err != nil {
+		return nil, err
+	}
+	// Documents are queued while rewriting, so the queue grows as it is processed.
+	for i := 0; i < len(b.queue); i++ {
+		doc := b.documents[b.queue[i]]
+		err = b.rewrite(doc)
+		if err != nil {
+			return nil, err
+		}
+		if m, ok := doc.root.(map[string]any); ok {
+			delete(m, "$schema")
+		}
+	}
+	if len(b.queue) == 0 {
+		return rootMap, nil
+	}
+	defs, ok := rootMap[defsKeyword].(map[string]any)
+	if !ok {
+		defs = map[string]any{}
+		rootMap[defsKeyword] = defs
+	}
+	for _, u := range b.queue {
+		defs[b.names[u]] = b.documents[u].root
+	}
+	return rootMap, nil
+}
+
+type document struct {
+	url       string
+	root      any
+	idKeyword string
+}
+
+// location is the position of a schema resource or anchor in a document.
+type location struct {
+	document string
+	pointer  string
+}
+
+type bundler struct {
+	entry       string
+	defsKeyword string
+	documents   map[string]*document
+	// resources maps the URLs of schema resources and anchors to their locations.
+	resources map[string]location
+	// names are the $defs names of inlined documents keyed by document URL.
+	names map[string]string
+	used  map[string]bool
+	// queue holds the inlined documents in the order they were found.
+	queue []string
+}
+
+// index records the resources and anchors in a document.
+func (b *bundler) index(doc *document) {
+	base, err := url.Parse(doc.url)
+	if err != nil {
+		return
+	}
+	b.resources[doc.url] = location{document: doc.url}
+	walk(doc.root, "", base, doc.idKeyword, func(m map[string]any, pointer string, base *url.URL) {
+		// Parents are visited before their children, so the first schema seen with a base URL is
+		// the root of that resource.
+		if _, ok := b.resources[base.String()]; !ok {
+			b.resources[base.String()] = location{document: doc.url, pointer: pointer}
+		}
+		var anchors []string
+		if anchor, ok := m["$anchor"].(string); ok {
+			anchors = append(anchors, anchor)
+		}
+		// Before 2019-09, anchors are declared with a fragment in the id.
+		if id, ok := m[doc.idKeyword].(string); ok {
+			_, fragment, found := strings.Cut(id, "#")
+			if found && fragment != "" && !strings.HasPrefix(fragment, "/") {
+				anchors = append(anchors, fragment)
+			}
+		}
+		for _, anchor := range anchors {
+			key := base.String() + "#" + anchor
+			if _, ok := b.resources[key]; !ok {
+				b.resources[key] = location{document: doc.url, pointer: pointer}
+			}
+		}
+	})
+}
+
+// rewrite rewrites the references in a document to point into the bundle.
+func (b *bundler) rewrite(doc *document) error {
+	base, err := url.Parse(doc.url)
+	if err != nil {
+		return fmt.Errorf("parsing URL %q: %w", doc.url, err)
+	}
+	walk(doc.root, "", base, doc.idKeyword, func(m map[string]any, pointer string, base *url.URL) {
+		if err != nil {
+			return
+		}
+		if ref, ok := m["$ref"].(string); ok {
+			var rewritten string
+			rewritten, err = b.rewriteRef(ref, base)
+			if err != nil {
+				err = fmt.Errorf("%s#%s: %w", doc.url, pointer, err)
+				return
+			}
+			m["$ref"] = rewritten
+		}
+		if pointer != "" || doc.url != b.entry {
+			delete(m, doc.idKeyword)
+			delete(m, "$anchor")
+		}
+	})
+	return err
+}
+
+// rewriteRef returns the reference to use in the bundle for ref.
+func (b *bundler) rewriteRef(ref string, base *url.URL) (string, error) {
+	target, err := base.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("parsing $ref %q: %w", ref, err)
+	}
+	fragment := target.Fragment
+	target.Fragment = ""
+	var loc location
+	var ok bool
+	if fragment == "" || strings.HasPrefix(fragment, "/") {
+		loc, ok = b.resources[target.String()]
+		loc.pointer += fragment
+	} else {
+		loc, ok = b.resources[target.String()+"#"+fragment]
+	}
+	if !ok {
+		if _, loaded := b.documents[target.String()]; loaded {
+			return "", fmt.Errorf("can't resolve $ref %q", ref)
+		}
+		return ref, nil
+	}
+	pointer := loc.pointer
+	if loc.document != b.entry {
+		pointer = "/" + b.defsKeyword + "/" + escapePointerToken(b.name(loc.document)) + pointer
+	}
+	if pointer == "" {
+		return "#", nil
+	}
+	return (&url.URL{Fragment: pointer}).String(), nil
+}
+
+// name returns the $defs name of an inlined document, queueing the document the first time.
+func (b *bundler) name(documentURL string) string {
+	if name, ok := b.names[documentURL]; ok {
+		return name
+	}
+	base := documentName(documentURL)
+	name := base
+	for i := 2; b.used[name]; i++ {
+		name = base + "_" + strconv.Itoa(i)
+	}
+	b.used[name] = true
+	b.names[documentURL] = name
+	b.queue = append(b.queue, documentURL)
+	return name
+}
+
+// documentName derives a $defs name from a document's file name without its extensions.
+func documentName(documentURL string) string {
+	var name string
+	parsed, err := url.Parse(documentURL)
+	if err == nil {
+		name = path.Base(parsed.Path)
+	}
+	for _, ext := range []string{".json", ".yaml", ".yml", ".schema"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+	if strings.Trim(name, "_") == "" {
+		return "schema"
+	}
+	return name
+}
+
+// idKeyword returns the keyword that sets a schema's id in a document.
+func idKeyword(root any) string {
+	m, _ := root.(map[string]any)
+	draft, _ := m["$schema"].(string)
+	if strings.Contains(draft, "draft-03") || strings.Contains(draft, "draft-04") {
+		return "id"
+	}
+	return "$id"
+}
+
+// definitionsKeyword returns the keyword for definitions in the bundle. Drafts before 2019-09 use
+// definitions.
+func definitionsKeyword(root map[string]any) string {
+	draft, _ := root["$schema"].(string)
+	for _, old := range []string{"draft-03", "draft-04", "draft-06", "draft-07"} {
+		if strings.Contains(draft, old) {
+			return "definitions"
+		}
+	}
+	return "$defs"
+}
+
+// Keywords whose values are a subschema, an array of subschemas or an object of subschemas.
+var (
+	schemaKeywords = []string{
+		"additionalItems", "additionalProperties", "contains", "contentSchema", "else", "if",
+		"items", "not", "propertyNames", "then", "unevaluatedItems", "unevaluatedProperties",
+	}
+	schemaArrayKeywords = []string{"allOf", "anyOf", "items", "oneOf", "prefixItems"}
+	schemaMapKeywords   = []string{
+		"$defs", "definitions", "dependencies", "dependentSchemas", "patternProperties", "properties",
+	}
+)
+
+// walk calls fn for every schema in node with its JSON pointer and base URL. Schemas are visited
+// before their subschemas, and object keys are visited in sorted order.
+func walk(node any, pointer string, base *url.URL, idKeyword string, fn func(m map[string]any, pointer string, base *url.URL)) {
+	m, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+	if id, ok := m[idKeyword].(string); ok && !strings.HasPrefix(id, "#") {
+		resolved, err := base.Parse(id)
+		if err == nil {
+			resolved.Fragment = ""
+			base = resolved
+		}
+	}
+	fn(m, pointer, base)
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		child := pointer + "/" + escapePointerToken(key)
+		switch value := m[key].(type) {
+		case map[string]any:
+			if slices.Contains(schemaMapKeywords, key) {
+				for _, name := range slices.Sorted(maps.Keys(value)) {
+					walk(value[name], child+"/"+escapePointerToken(name), base, idKeyword, fn)
+				}
+			} else if slices.Contains(schemaKeywords, key) {
+				walk(value, child, base, idKeyword, fn)
+			}
+		case []any:
+			if slices.Contains(schemaArrayKeywords, key) {
+				for i, item := range value {
+					walk(item, child+"/"+strconv.Itoa(i), base, idKeyword, fn)
+				}
+			}
+		}
+	}
+}
+
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
@@ -0,0 +1,126 @@
+package schemabundle_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/schemabundle"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o700))
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+	}
+	return dir
+}
+
+// bundle bundles an entry schema and returns the result as JSON.
+func bundle(t *testing.T, entry string, opts *schemaloader.Options) string {
+	t.Helper()
+	bundled, err := schemabundle.Bundle(entry, opts)
+	require.NoError(t, err)
+	data, err := json.Marshal(bundled)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestBundle(t *testing.T) {
+	t.Run("external documents", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"entry.json": `{
+				"type": "object",
+				"properties": {
+					"person": {"$ref": "common/person.json"},
+					"amount": {"$ref": "common/money.yaml#/$defs/amount"},
+					"local": {"$ref": "#/$defs/person"}
+				},
+				"$defs": {"person": {"type": "null"}}
+			}`,
+			"common/person.json": `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"wallet": {"$ref": "money.yaml"},
+					"name": {"$ref": "#name"},
+					"entry": {"$ref": "../entry.json#/properties/local"}
+				},
+				"$defs": {"name": {"$anchor": "name", "type": "string"}}
+			}`,
+			"common/money.yaml": "$defs:\n  amount:\n    type: number\ntype: object\n",
+		})
+		got := bundle(t, filepath.Join(dir, "entry.json"), nil)
+		assert.JSONEq(t, `{
+			"type": "object",
+			"properties": {
+				"person": {"$ref": "#/$defs/person_2"},
+				"amount": {"$ref": "#/$defs/money/$defs/amount"},
+				"local": {"$ref": "#/$defs/person"}
+			},
+			"$defs": {
+				"person": {"type": "null"},
+				"money": {"$defs": {"amount": {"type": "number"}}, "type": "object"},
+				"person_2": {
+					"type": "object",
+					"properties": {
+						"wallet": {"$ref": "#/$defs/money"},
+						"name": {"$ref": "#/$defs/person_2/$defs/name"},
+						"entry": {"$ref": "#/properties/local"}
+					},
+					"$defs": {"name": {"type": "string"}}
+				}
+			}
+		}`, got)
+	})
+
+	t.Run("embedded ids", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{
+			"entry.json": `{
+				"$schema": "http://json-schema.org/draft-07/schema#",
+				"$id": "https://example.com/entry.json",
+				"properties": {
+					"a": {"$ref": "https://example.com/a.json"},
+					"b": {"$ref": "b.json#/definitions/b"}
+				},
+				"definitions": {
+					"embedded": {"$id": "https://example.com/a.json", "type": "string"}
+				}
+			}`,
+			"b.json": `{"$schema": "http://json-schema.org/draft-07/schema#", "definitions": {"b": {"$ref": "#bee"}, "bee": {"$id": "#bee", "type": "integer"}}}`,
+		})
+		got := bundle(t, filepath.Join(dir, "entry.json"), &schemaloader.Options{
+			Mappings: map[string]string{"https://example.com/": dir},
+		})
+		assert.JSONEq(t, `{
+			"$schema": "http://json-schema.org/draft-07/schema#",
+			"$id": "https://example.com/entry.json",
+			"properties": {
+				"a": {"$ref": "#/definitions/embedded"},
+				"b": {"$ref": "#/definitions/b/definitions/b"}
+			},
+			"definitions": {
+				"embedded": {"type": "string"},
+				"b": {"definitions": {"b": {"$ref": "#/definitions/b/definitions/bee"}, "bee": {"type": "integer"}}}
+			}
+		}`, got)
+	})
+
+	t.Run("no references", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"entry.json": `{"type": "string", "enum": ["$ref"]}`})
+		assert.JSONEq(t, `{"type": "string", "enum": ["$ref"]}`, bundle(t, filepath.Join(dir, "entry.json"), nil))
+	})
+
+	t.Run("unresolvable reference", func(t *testing.T) {
+		dir := writeFiles(t, map[string]string{"entry.json": `{"$ref": "missing.json"}`})
+		_, err := schemabundle.Bundle(filepath.Join(dir, "entry.json"), nil)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,162 @@
+package schemaloader
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// authTransport adds the configured headers and credentials for a request's host. It runs for
+// every request, including redirects, so credentials are only sent to the hosts they belong to.
+type authTransport struct {
+	base         http.RoundTripper
+	headers      map[string]http.Header
+	bearerTokens map[string]string
+	netrc        []netrcEntry
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	hostname := req.URL.Hostname()
+	headers := lookupHost(t.headers, host, hostname)
+	token := lookupHost(t.bearerTokens, host, hostname)
+	netrc, hasNetrc := findNetrc(t.netrc, hostname)
+	if len(headers) == 0 && token == "" && !hasNetrc {
+		return t.base.RoundTrip(req)
+	}
+	// RoundTrippers must not modify the request.
+	req = req.Clone(req.Context())
+	for name, values := range headers {
+		req.Header[http.CanonicalHeaderKey(name)] = values
+	}
+	if req.Header.Get("Authorization") == "" {
+		switch {
+		case token != "":
+			req.Header.Set("Authorization", "Bearer "+token)
+		case hasNetrc:
+			req.SetBasicAuth(netrc.login, netrc.password)
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// lookupHost returns the value for host, which includes the port when there is one, or for
+// hostname.
+func lookupHost[V any](mp map[string]V, host, hostname string) V {
+	if v, ok := mp[host]; ok {
+		return v
+	}
+	return mp[hostname]
+}
+
+type netrcEntry struct {
+	machine  string // empty for the default entry
+	login    string
+	password string
+}
+
+// findNetrc returns the entry for hostname, falling back to the default entry.
+func findNetrc(entries []netrcEntry, hostname string) (netrcEntry, bool) {
+	var fallback *netrcEntry
+	for i, entry := range entries {
+		if entry.machine == hostname {
+			return entry, true
+		}
+		if entry.machine == "" && fallback == nil {
+			fallback = &entries[i]
+		}
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return netrcEntry{}, false
+}
+
+// DefaultNetrcFile returns the netrc file named by the NETRC environment variable, or .netrc in the
+// home directory (_netrc on Windows).
+func DefaultNetrcFile() (string, error) {
+	if file := os.Getenv("NETRC"); file != "" {
+		return file, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(home, name), nil
+}
+
+// readNetrc reads the machine entries from a netrc file. A missing file has no entries.
+func readNetrc(filename string) ([]netrcEntry, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading netrc: %w", err)
+	}
+	return parseNetrc(string(data)), nil
+}
+
+// parseNetrc parses the machine, default, login and password tokens of a netrc file. Macro
+// definitions are skipped.
+func parseNetrc(data string) []netrcEntry {
+	var entries []netrcEntry
+	var current *netrcEntry
+	lines := bufio.NewScanner(strings.NewReader(data))
+	inMacro := false
+	for lines.Scan() {
+		line := lines.Text()
+		if inMacro {
+			// A macro definition ends with an empty line.
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields); i++ {
+			if strings.HasPrefix(fields[i], "#") {
+				break
+			}
+			next := func() string {
+				if i+1 >= len(fields) {
+					return ""
+				}
+				i++
+				return fields[i]
+			}
+			switch fields[i] {
+			case "machine":
+				entries = append(entries, netrcEntry{machine: next()})
+				current = &entries[len(entries)-1]
+			case "default":
+				entries = append(entries, netrcEntry{})
+				current = &entries[len(entries)-1]
+			case "login":
+				value := next()
+				if current != nil {
+					current.login = value
+				}
+			case "password":
+				value := next()
+				if current != nil {
+					current.password = value
+				}
+			case "account":
+				next()
+			case "macdef":
+				inMacro = true
+				i = len(fields)
+			}
+		}
+	}
+	return entries
+}
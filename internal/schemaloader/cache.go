@@ -0,0 +1,101 @@
+package schemaloader
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// httpCache stores remote documents in a directory. Every URL has a body file and a metadata file
+// named after the SHA-256 of the URL.
+type httpCache struct {
+	dir string
+}
+
+// cacheEntry is the metadata stored with a cached document. The validators are sent with the next
+// request so the server can answer 304 Not Modified.
+type cacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (c *httpCache) paths(u string) (meta, body string) {
+	sum := sha256.Sum256([]byte(u))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, name+".json"), filepath.Join(c.dir, name+".body")
+}
+
+// get returns the cached entry and body for u. It returns false when u isn't cached.
+func (c *httpCache) get(u string) (*cacheEntry, []byte, bool, error) {
+	metaFile, bodyFile := c.paths(u)
+	metaData, err := os.ReadFile(metaFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("reading cache: %w", err)
+	}
+	var entry cacheEntry
+	err = json.Unmarshal(metaData, &entry)
+	if err != nil || entry.URL != u {
+		// A corrupt entry is treated as a miss and replaced by the next fetch.
+		return nil, nil, false, nil
+	}
+	body, err := os.ReadFile(bodyFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, false, nil
+	}
+	if err != nil {
+		return nil, nil, false, fmt.Errorf("reading cache: %w", err)
+	}
+	return &entry, body, true, nil
+}
+
+// put stores body and its validators. The body is written before the metadata so that an
+// interrupted write is never mistaken for a complete entry.
+func (c *httpCache) put(entry *cacheEntry, body []byte) error {
+	err := os.MkdirAll(c.dir, 0o700)
+	if err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	metaFile, bodyFile := c.paths(entry.URL)
+	metaData, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(bodyFile, body)
+	if err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	err = writeFileAtomic(metaFile, metaData)
+	if err != nil {
+		return fmt.Errorf("writing cache: %w", err)
+	}
+	return nil
+}
+
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Close()
+	} else {
+		_ = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filename)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
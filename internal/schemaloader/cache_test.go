@@ -0,0 +1,111 @@
+package schemaloader_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+type schemaServer struct {
+	*httptest.Server
+	requests    atomic.Int32
+	notModified atomic.Int32
+	body        atomic.Value
+}
+
+func newSchemaServer(t *testing.T) *schemaServer {
+	t.Helper()
+	s := &schemaServer{}
+	s.body.Store(`{"type": "string"}`)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		body, _ := s.body.Load().(string)
+		etag := `"` + body + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			s.notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func load(t *testing.T, opts *schemaloader.Options, u string) (any, error) {
+	t.Helper()
+	loader, err := schemaloader.New(nil, opts)
+	require.NoError(t, err)
+	return loader.Load(u)
+}
+
+func TestCache(t *testing.T) {
+	t.Run("revalidates cached documents", func(t *testing.T) {
+		server := newSchemaServer(t)
+		opts := &schemaloader.Options{CacheDir: t.TempDir()}
+		u := server.URL + "/schema.json"
+
+		got, err := load(t, opts, u)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"type": "string"}, got)
+
+		got, err = load(t, opts, u)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"type": "string"}, got)
+		require.Equal(t, int32(2), server.requests.Load())
+		require.Equal(t, int32(1), server.notModified.Load())
+
+		server.body.Store(`{"type": "integer"}`)
+		got, err = load(t, opts, u)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"type": "integer"}, got)
+	})
+
+	t.Run("offline", func(t *testing.T) {
+		server := newSchemaServer(t)
+		cacheDir := t.TempDir()
+		u := server.URL + "/schema.json"
+
+		_, err := load(t, &schemaloader.Options{CacheDir: cacheDir, Offline: true}, u)
+		require.EqualError(t, err, u+" is not cached and can't be fetched offline")
+		require.Equal(t, int32(0), server.requests.Load())
+
+		_, err = load(t, &schemaloader.Options{CacheDir: cacheDir}, u)
+		require.NoError(t, err)
+		server.Close()
+
+		got, err := load(t, &schemaloader.Options{CacheDir: cacheDir, Offline: true}, u)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"type": "string"}, got)
+	})
+
+	t.Run("refresh", func(t *testing.T) {
+		server := newSchemaServer(t)
+		cacheDir := t.TempDir()
+		u := server.URL + "/schema.json"
+
+		_, err := load(t, &schemaloader.Options{CacheDir: cacheDir}, u)
+		require.NoError(t, err)
+		_, err = load(t, &schemaloader.Options{CacheDir: cacheDir, Refresh: true}, u)
+		require.NoError(t, err)
+		require.Equal(t, int32(2), server.requests.Load())
+		require.Equal(t, int32(0), server.notModified.Load())
+	})
+
+	t.Run("offline requires a cache directory", func(t *testing.T) {
+		_, err := schemaloader.New(nil, &schemaloader.Options{Offline: true})
+		require.EqualError(t, err, "offline mode requires a cache directory")
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		t.Cleanup(server.Close)
+		_, err := load(t, &schemaloader.Options{CacheDir: t.TempDir()}, server.URL+"/missing.json")
+		require.EqualError(t, err, server.URL+"/missing.json returned status code 404")
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 )
 
 func tlsConfigWithCACert(tlsConfig *tls.Config, cacert string) (*tls.Config, error) {
@@ -51,18 +52,45 @@ func setupTLSConfig(transport *http.Transport, insecure bool, cacert string) err
 	return nil
 }
 
-func newHTTPClient(insecure bool, cacert string) (*http.Client, error) {
+// defaultTimeout is the HTTP request timeout when Options.Timeout is zero.
+const defaultTimeout = 15 * time.Second
+
+func newHTTPClient(opts *Options) (*http.Client, error) {
 	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
 	if !ok {
 		return nil, fmt.Errorf("default transport is not *http.Transport")
 	}
 	transport := defaultTransport.Clone()
-	err := setupTLSConfig(transport, insecure, cacert)
+	err := setupTLSConfig(transport, opts.Insecure, opts.CACert)
 	if err != nil {
 		return nil, err
 	}
+	var netrc []netrcEntry
+	if opts.Netrc != "" {
+		netrc, err = readNetrc(opts.Netrc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = defaultTimeout
+	}
 	return &http.Client{
-		Transport: transport,
-		Timeout:   15 * 1000, // 15 seconds
+		Transport: &authTransport{
+			base:         transport,
+			headers:      opts.Headers,
+			bearerTokens: opts.BearerTokens,
+			netrc:        netrc,
+		},
+		Timeout: timeout,
 	}, nil
 }
+
+// retryable reports whether a request that got resp and err should be retried.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,163 @@
+package schemaloader_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+// requestRecorder is a server that records the headers of the last request.
+func requestRecorder(t *testing.T) (*httptest.Server, *atomic.Pointer[http.Header]) {
+	t.Helper()
+	var last atomic.Pointer[http.Header]
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Clone()
+		last.Store(&header)
+		_, _ = w.Write([]byte(`{"type": "string"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &last
+}
+
+func TestHTTPAuth(t *testing.T) {
+	server, last := requestRecorder(t)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	host := serverURL.Host
+	hostname := serverURL.Hostname()
+
+	netrc := filepath.Join(t.TempDir(), "netrc")
+	require.NoError(t, os.WriteFile(netrc, []byte(`
+# comment
+machine other.example.com login other password other
+macdef init
+  cd /pub
+
+machine `+hostname+`
+  login user
+  password secret
+default login anonymous password guest
+`), 0o600))
+
+	for _, test := range []struct {
+		name string
+		opts schemaloader.Options
+		want map[string]string
+	}{
+		{
+			name: "headers",
+			opts: schemaloader.Options{
+				Headers: map[string]http.Header{
+					host:                {"X-Api-Key": {"key"}},
+					"other.example.com": {"X-Other": {"other"}},
+				},
+			},
+			want: map[string]string{"X-Api-Key": "key", "X-Other": "", "Authorization": ""},
+		},
+		{
+			name: "bearer token by hostname",
+			opts: schemaloader.Options{
+				BearerTokens: map[string]string{hostname: "token"},
+			},
+			want: map[string]string{"Authorization": "Bearer token"},
+		},
+		{
+			name: "netrc",
+			opts: schemaloader.Options{Netrc: netrc},
+			want: map[string]string{"Authorization": "Basic dXNlcjpzZWNyZXQ="},
+		},
+		{
+			name: "bearer token before netrc",
+			opts: schemaloader.Options{
+				Netrc:        netrc,
+				BearerTokens: map[string]string{host: "token"},
+			},
+			want: map[string]string{"Authorization": "Bearer token"},
+		},
+		{
+			name: "authorization header before bearer token",
+			opts: schemaloader.Options{
+				Headers:      map[string]http.Header{host: {"Authorization": {"Custom xyz"}}},
+				BearerTokens: map[string]string{host: "token"},
+			},
+			want: map[string]string{"Authorization": "Custom xyz"},
+		},
+		{
+			name: "missing netrc",
+			opts: schemaloader.Options{Netrc: filepath.Join(t.TempDir(), "missing")},
+			want: map[string]string{"Authorization": ""},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := load(t, &test.opts, server.URL+"/schema.json")
+			require.NoError(t, err)
+			header := last.Load()
+			require.NotNil(t, header)
+			for name, value := range test.want {
+				assert.Equal(t, value, header.Get(name), name)
+			}
+		})
+	}
+}
+
+func TestHTTPRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"type": "string"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("gives up", func(t *testing.T) {
+		requests.Store(0)
+		_, err := load(t, &schemaloader.Options{Retries: 1, RetryDelay: time.Millisecond}, server.URL+"/schema.json")
+		require.EqualError(t, err, server.URL+"/schema.json returned status code 503")
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("succeeds", func(t *testing.T) {
+		requests.Store(0)
+		got, err := load(t, &schemaloader.Options{Retries: 2, RetryDelay: time.Millisecond}, server.URL+"/schema.json")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"type": "string"}, got)
+		assert.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var notFound atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			notFound.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		t.Cleanup(server.Close)
+		_, err := load(t, &schemaloader.Options{Retries: 2, RetryDelay: time.Millisecond}, server.URL+"/schema.json")
+		require.Error(t, err)
+		assert.Equal(t, int32(1), notFound.Load())
+	})
+}
+
+func TestHTTPTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		_, _ = w.Write([]byte(`{"type": "string"}`))
+	}))
+	t.Cleanup(server.Close)
+	_, err := load(t, &schemaloader.Options{Timeout: 10 * time.Millisecond}, server.URL+"/schema.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Client.Timeout exceeded")
+}
@@ -0,0 +1,158 @@
+package schemaloader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Outline is the structure of a document that Decode loses by decoding objects into maps.
+type Outline struct {
+	// Keys are the keys of every object in the order they are declared. They are keyed by the JSON
+	// pointer of the object, which is empty for the root.
+	Keys map[string][]string
+	// Positions are the positions of every value keyed by JSON pointer. The position of an object
+	// member is the position of its key.
+	Positions map[string]Position
+}
+
+// Position is a line and column in a document. Both start at 1.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// OutlineDocument returns the outline of a JSON or YAML document.
+func OutlineDocument(data []byte) (*Outline, error) {
+	outline := &Outline{
+		Keys:      map[string][]string{},
+		Positions: map[string]Position{},
+	}
+	if json.Valid(data) {
+		o := &jsonOutliner{
+			data:    data,
+			decoder: json.NewDecoder(bytes.NewReader(data)),
+			outline: outline,
+		}
+		o.decoder.UseNumber()
+		err := o.value("", o.skip(0))
+		if err != nil {
+			return nil, err
+		}
+		return outline, nil
+	}
+	var node yaml.Node
+	err := yaml.Unmarshal(data, &node)
+	if err != nil {
+		return nil, err
+	}
+	yamlOutline(&node, "", Position{Line: node.Line, Column: node.Column}, outline)
+	return outline, nil
+}
+
+type jsonOutliner struct {
+	data    []byte
+	decoder *json.Decoder
+	outline *Outline
+}
+
+// skip returns the offset of the next token after offset.
+func (o *jsonOutliner) skip(offset int64) int64 {
+	for offset < int64(len(o.data)) && strings.IndexByte(" \t\r\n,:", o.data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+func (o *jsonOutliner) position(offset int64) Position {
+	before := o.data[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return Position{
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+	}
+}
+
+// value reads one value from the decoder and records the outline of it. start is the offset of
+// the value or, for object members, of the key.
+func (o *jsonOutliner) value(pointer string, start int64) error {
+	o.outline.Positions[pointer] = o.position(start)
+	token, err := o.decoder.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		keys := []string{}
+		for o.decoder.More() {
+			keyStart := o.skip(o.decoder.InputOffset())
+			token, err = o.decoder.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := token.(string)
+			if !ok {
+				return fmt.Errorf("unexpected object key %v", token)
+			}
+			keys = append(keys, key)
+			err = o.value(pointer+"/"+escapePointerToken(key), keyStart)
+			if err != nil {
+				return err
+			}
+		}
+		o.outline.Keys[pointer] = keys
+	case '[':
+		for i := 0; o.decoder.More(); i++ {
+			err = o.value(pointer+"/"+strconv.Itoa(i), o.skip(o.decoder.InputOffset()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	// Read the closing delimiter.
+	_, err = o.decoder.Token()
+	return err
+}
+
+func yamlOutline(node *yaml.Node, pointer string, position Position, outline *Outline) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			content := node.Content[0]
+			yamlOutline(content, pointer, Position{Line: content.Line, Column: content.Column}, outline)
+		}
+		return
+	case yaml.AliasNode:
+		yamlOutline(node.Alias, pointer, position, outline)
+		return
+	}
+	outline.Positions[pointer] = position
+	switch node.Kind {
+	case yaml.MappingNode:
+		keys := []string{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keys = append(keys, key.Value)
+			yamlOutline(node.Content[i+1], pointer+"/"+escapePointerToken(key.Value), Position{Line: key.Line, Column: key.Column}, outline)
+		}
+		outline.Keys[pointer] = keys
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			yamlOutline(item, pointer+"/"+strconv.Itoa(i), Position{Line: item.Line, Column: item.Column}, outline)
+		}
+	}
+}
+
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	return strings.ReplaceAll(token, "/", "~1")
+}
@@ -0,0 +1,44 @@
+package schemaloader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+func TestOutlineDocument(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		got, err := schemaloader.OutlineDocument([]byte("{\"z\": 1,\n  \"a/b\": {\"y\": [{\"c\": \"é\", \"b\": 2}],\n   \"x\": null}}"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"":          {"z", "a/b"},
+			"/a~1b":     {"y", "x"},
+			"/a~1b/y/0": {"c", "b"},
+		}, got.Keys)
+		assert.Equal(t, map[string]schemaloader.Position{
+			"":            {Line: 1, Column: 1},
+			"/z":          {Line: 1, Column: 2},
+			"/a~1b":       {Line: 2, Column: 3},
+			"/a~1b/y":     {Line: 2, Column: 11},
+			"/a~1b/y/0":   {Line: 2, Column: 17},
+			"/a~1b/y/0/c": {Line: 2, Column: 18},
+			"/a~1b/y/0/b": {Line: 2, Column: 28},
+			"/a~1b/x":     {Line: 3, Column: 4},
+		}, got.Positions)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		got, err := schemaloader.OutlineDocument([]byte("z: &z\n  b: 1\n  a: 2\nm:\n  - *z\n"))
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"":     {"z", "m"},
+			"/z":   {"b", "a"},
+			"/m/0": {"b", "a"},
+		}, got.Keys)
+		assert.Equal(t, schemaloader.Position{Line: 3, Column: 3}, got.Positions["/z/a"])
+		assert.Equal(t, schemaloader.Position{Line: 5, Column: 5}, got.Positions["/m/0"])
+		assert.Equal(t, schemaloader.Position{Line: 3, Column: 3}, got.Positions["/m/0/a"])
+	})
+}
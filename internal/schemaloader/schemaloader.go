@@ -3,15 +3,20 @@ package schemaloader
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"gopkg.in/yaml.v3"
@@ -24,10 +29,28 @@ type OnLoadFunc func(uri string, schema any)
 type URLLoader struct {
 	mappingsLoader mappingsLoader
 	onLoad         OnLoadFunc
+	onRead         func(uri string, data []byte)
+	checksums      map[string]string
 }
 
 func (s *URLLoader) Load(u string) (any, error) {
-	schema, err := s.mappingsLoader.Load(u)
+	if s.checksums != nil && IsRemote(u) {
+		if _, ok := s.checksums[u]; !ok {
+			return nil, fmt.Errorf("%s has no checksum in the lockfile", u)
+		}
+	}
+	data, err := s.mappingsLoader.read(u)
+	if err != nil {
+		return nil, err
+	}
+	err = s.verify(u, data)
+	if err != nil {
+		return nil, err
+	}
+	if s.onRead != nil {
+		s.onRead(u, data)
+	}
+	schema, err := Decode(data)
 	if err != nil {
 		return nil, err
 	}
@@ -38,38 +61,122 @@ func (s *URLLoader) Load(u string) (any, error) {
 }
 
 type Options struct {
+	// Context is used for HTTP requests. Default: context.Background()
+	Context context.Context
+	// Mappings map URL prefixes to local directories. A URL under a prefix is read from the
+	// directory at the path that follows the prefix. The longest matching prefix wins.
 	Mappings map[string]string
+	// Sources are schema documents keyed by URL. They are used instead of loading the URL.
+	Sources map[string][]byte
+	// FS mounts file systems at URL prefixes. A URL under a prefix is read from the file system at
+	// the path that follows the prefix. The longest matching prefix wins.
+	FS       map[string]fs.FS
 	CACert   string
 	Insecure bool
+	// CacheDir is a directory for caching remote documents. Cached documents are revalidated with
+	// their ETag or Last-Modified validators. No caching when empty.
+	CacheDir string
+	// Offline loads remote documents only from CacheDir and fails when one isn't cached.
+	Offline bool
+	// Refresh fetches remote documents again instead of revalidating cached copies.
+	Refresh bool
+	// Checksums are the hex encoded SHA-256 checksums of documents keyed by URL. When set, a
+	// document with a checksum fails to load when its content doesn't match, and remote documents
+	// without a checksum fail to load.
+	Checksums map[string]string
+	// OnRead is called with the content of every document before it is parsed.
+	OnRead func(uri string, data []byte)
+	// Headers are added to HTTP requests. They are keyed by host, with or without a port.
+	Headers map[string]http.Header
+	// BearerTokens are sent in the Authorization header of HTTP requests. They are keyed by host,
+	// with or without a port.
+	BearerTokens map[string]string
+	// Netrc is a netrc file with credentials for HTTP basic authentication. Bearer tokens and
+	// Authorization headers take precedence. A missing file is ignored.
+	Netrc string
+	// Timeout for each HTTP request. Default: 15s
+	Timeout time.Duration
+	// Retries is how many times an HTTP request is retried after a network error or a 429 or 5xx
+	// response.
+	Retries int
+	// RetryDelay is the delay before the first retry. It doubles for every retry. Default: 250ms
+	RetryDelay time.Duration
 }
 
 func New(onLoad OnLoadFunc, opts *Options) (*URLLoader, error) {
 	if opts == nil {
 		opts = &Options{}
 	}
-	httpClient, err := newHTTPClient(opts.Insecure, opts.CACert)
+	httpClient, err := newHTTPClient(opts)
 	if err != nil {
 		return nil, err
 	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Offline && opts.CacheDir == "" {
+		return nil, fmt.Errorf("offline mode requires a cache directory")
+	}
+	if opts.Offline && opts.Refresh {
+		return nil, fmt.Errorf("offline and refresh can't be used together")
+	}
+	remote := &httpLoader{
+		client:     httpClient,
+		ctx:        ctx,
+		offline:    opts.Offline,
+		refresh:    opts.Refresh,
+		retries:    opts.Retries,
+		retryDelay: opts.RetryDelay,
+	}
+	if remote.retryDelay == 0 {
+		remote.retryDelay = 250 * time.Millisecond
+	}
+	if opts.CacheDir != "" {
+		remote.cache = &httpCache{dir: opts.CacheDir}
+	}
 	return &URLLoader{
-		onLoad: onLoad,
+		onLoad:    onLoad,
+		onRead:    opts.OnRead,
+		checksums: opts.Checksums,
 		mappingsLoader: mappingsLoader{
 			mappings: opts.Mappings,
-			fallback: jsonschema.SchemeURLLoader{
-				"file":  loaderFunc(loadFile),
-				"":      loaderFunc(loadFile),
-				"http":  (*httpLoader)(httpClient),
-				"https": (*httpLoader)(httpClient),
+			sources:  opts.Sources,
+			mounts:   opts.FS,
+			schemes: map[string]reader{
+				"file":  readerFunc(readFile),
+				"":      readerFunc(readFile),
+				"http":  remote,
+				"https": remote,
 			},
 		},
 	}, nil
 }
 
-type loaderFunc func(string) (any, error)
+// ToURL converts a local file path to a file URL. Values that are already URLs are returned
+// unchanged.
+func ToURL(pathOrURL string) (string, error) {
+	if strings.Contains(pathOrURL, "://") {
+		return pathOrURL, nil
+	}
+	absPath, err := filepath.Abs(pathOrURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return "file://" + filepath.ToSlash(absPath), nil
+}
+
+// reader reads the content of a document.
+type reader interface {
+	read(u string) ([]byte, error)
+}
+
+type readerFunc func(string) ([]byte, error)
 
-func (f loaderFunc) Load(u string) (any, error) { return f(u) }
+func (f readerFunc) read(u string) ([]byte, error) { return f(u) }
 
-func loadBytes(data []byte) (any, error) {
+// Decode parses a JSON or YAML document. JSON numbers are decoded as json.Number.
+func Decode(data []byte) (any, error) {
 	if json.Valid(data) {
 		return jsonschema.UnmarshalJSON(bytes.NewReader(data))
 	}
@@ -81,7 +188,7 @@ func loadBytes(data []byte) (any, error) {
 	return v, nil
 }
 
-func loadFile(u string) (any, error) {
+func readFile(u string) ([]byte, error) {
 	parsedURL, err := url.Parse(u)
 	if err != nil {
 		return nil, fmt.Errorf("parsing URL %q: %w", u, err)
@@ -96,26 +203,61 @@ func loadFile(u string) (any, error) {
 		filename = strings.TrimPrefix(filename, "/")
 	}
 
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
-	return loadBytes(data)
+	return os.ReadFile(filename)
 }
 
-type httpLoader http.Client
+type httpLoader struct {
+	client *http.Client
+	// ctx is stored because jsonschema.URLLoader has no context parameter.
+	ctx        context.Context
+	cache      *httpCache
+	offline    bool
+	refresh    bool
+	retries    int
+	retryDelay time.Duration
+}
 
-func (l *httpLoader) Load(u string) (_ any, errOut error) {
-	client := (*http.Client)(l)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, u, http.NoBody)
+// read returns the document at u. Cached documents are revalidated with the server unless the
+// loader is offline.
+func (l *httpLoader) read(u string) (_ []byte, errOut error) {
+	var cached *cacheEntry
+	var cachedBody []byte
+	if l.cache != nil && !l.refresh {
+		entry, body, ok, err := l.cache.get(u)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			cached, cachedBody = entry, body
+		}
+	}
+	if l.offline {
+		if cached == nil {
+			return nil, fmt.Errorf("%s is not cached and can't be fetched offline", u)
+		}
+		return cachedBody, nil
+	}
+
+	req, err := http.NewRequestWithContext(l.ctx, http.MethodGet, u, http.NoBody)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
-	resp, err := client.Do(req)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := l.do(req)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { errOut = errors.Join(resp.Body.Close()) }()
+	defer func() { errOut = errors.Join(errOut, resp.Body.Close()) }()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cachedBody, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("%s returned status code %d", u, resp.StatusCode)
 	}
@@ -123,20 +265,115 @@ func (l *httpLoader) Load(u string) (_ any, errOut error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading response body: %w", err)
 	}
-	return loadBytes(b)
+	if l.cache != nil {
+		err = l.cache.put(&cacheEntry{
+			URL:          u,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// do sends req and retries it after network errors and 429 and 5xx responses.
+func (l *httpLoader) do(req *http.Request) (*http.Response, error) {
+	delay := l.retryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := l.client.Do(req)
+		if attempt >= l.retries || l.ctx.Err() != nil || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		select {
+		case <-l.ctx.Done():
+			return nil, l.ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
 }
 
 type mappingsLoader struct {
 	mappings map[string]string
-	fallback jsonschema.URLLoader
+	sources  map[string][]byte
+	mounts   map[string]fs.FS
+	schemes  map[string]reader
 }
 
-func (l *mappingsLoader) Load(u string) (any, error) {
-	for prefix, dir := range l.mappings {
-		suffix, ok := strings.CutPrefix(u, prefix)
-		if ok {
-			return loadFile(filepath.Join(dir, suffix))
+func (l *mappingsLoader) read(u string) ([]byte, error) {
+	if data, ok := l.sources[u]; ok {
+		return data, nil
+	}
+	if fsys, name, ok := l.mount(u); ok {
+		return fs.ReadFile(fsys, name)
+	}
+	if filename, ok := l.mapping(u); ok {
+		return readFile(filename)
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL %q: %w", u, err)
+	}
+	schemeReader, ok := l.schemes[parsed.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported URL scheme %q in %s", parsed.Scheme, u)
+	}
+	return schemeReader.read(u)
+}
+
+// mapping returns the file that the longest mapped prefix of u maps it to.
+func (l *mappingsLoader) mapping(u string) (string, bool) {
+	found, ok := "", false
+	for prefix := range l.mappings {
+		if strings.HasPrefix(u, prefix) && (!ok || len(prefix) > len(found)) {
+			found, ok = prefix, true
+		}
+	}
+	if !ok {
+		return "", false
+	}
+	return filepath.Join(l.mappings[found], strings.TrimPrefix(u, found)), true
+}
+
+// mount returns the file system mounted at the longest prefix of u and the name of u in it.
+func (l *mappingsLoader) mount(u string) (fs.FS, string, bool) {
+	var found string
+	for prefix := range l.mounts {
+		if strings.HasPrefix(u, prefix) && len(prefix) > len(found) {
+			found = prefix
 		}
 	}
-	return l.fallback.Load(u)
+	if found == "" {
+		return nil, "", false
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(u, found), "/")
+	name = path.Clean(name)
+	return l.mounts[found], name, true
+}
+
+// verify checks data against the checksum for u.
+func (s *URLLoader) verify(u string, data []byte) error {
+	if s.checksums == nil {
+		return nil
+	}
+	want, ok := s.checksums[u]
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	if got != want {
+		return fmt.Errorf("checksum mismatch for %s: got sha256 %s, want %s", u, got, want)
+	}
+	return nil
+}
+
+// IsRemote reports whether u is an http or https URL.
+func IsRemote(u string) bool {
+	return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")
 }
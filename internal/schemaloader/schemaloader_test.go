@@ -0,0 +1,31 @@
+package schemaloader_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+func TestMappings(t *testing.T) {
+	short := t.TempDir()
+	long := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(short, "schemas"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(short, "schemas", "person.json"), []byte(`{"title": "short"}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(long, "person.json"), []byte(`{"title": "long"}`), 0o600))
+	opts := &schemaloader.Options{
+		Mappings: map[string]string{
+			"https://example.com/":         short,
+			"https://example.com/schemas/": long,
+		},
+	}
+	// Map iteration order is random, so load repeatedly to catch a first match winning.
+	for range 20 {
+		doc, err := load(t, opts, "https://example.com/schemas/person.json")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"title": "long"}, doc)
+	}
+}
@@ -0,0 +1,180 @@
+// Package schemavendor copies remote schema documents into a local directory and records their
+// checksums in a lockfile so that later runs load exactly the same content.
+package schemavendor
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/willabides/jsonschematogo/internal/schema"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultDir is the directory that vendored documents are written to by default.
+const DefaultDir = "vendored-schemas"
+
+// LockfileName is the name of the lockfile in a vendor directory.
+const LockfileName = "jsonschematogo.lock"
+
+const lockfileHeader = "# Code generated by jsonschematogo vendor. DO NOT EDIT.\n"
+
+// Lock is the content of a lockfile.
+type Lock struct {
+	// URLMap maps URL prefixes to the directories that hold the vendored documents. The
+	// directories are relative to the lockfile in the file and resolved by ReadLock.
+	URLMap map[string]string `yaml:"url-map"`
+	// Schemas are the vendored documents sorted by URL.
+	Schemas []LockedSchema `yaml:"schemas"`
+}
+
+// LockedSchema is a vendored document.
+type LockedSchema struct {
+	URL    string `yaml:"url"`
+	SHA256 string `yaml:"sha256"`
+}
+
+// Checksums returns the checksums of the vendored documents keyed by URL.
+func (l *Lock) Checksums() map[string]string {
+	checksums := make(map[string]string, len(l.Schemas))
+	for _, locked := range l.Schemas {
+		checksums[locked.URL] = locked.SHA256
+	}
+	return checksums
+}
+
+// ReadLock reads a lockfile. The directories in URLMap are resolved relative to the lockfile.
+func ReadLock(filename string) (*Lock, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading lockfile: %w", err)
+	}
+	var lock Lock
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(&lock)
+	if err != nil {
+		return nil, fmt.Errorf("decoding lockfile %s: %w", filename, err)
+	}
+	dir := filepath.Dir(filename)
+	for prefix, target := range lock.URLMap {
+		lock.URLMap[prefix] = filepath.Join(dir, filepath.FromSlash(target))
+	}
+	return &lock, nil
+}
+
+// Crawl loads entry schemas and everything they reference. It returns the content of the remote
+// documents keyed by URL. opts.Checksums is ignored so that changed documents can be vendored
+// again.
+func Crawl(entries []string, opts *schemaloader.Options) (map[string][]byte, error) {
+	loaderOpts := schemaloader.Options{}
+	if opts != nil {
+		loaderOpts = *opts
+	}
+	loaderOpts.Checksums = nil
+	documents := map[string][]byte{}
+	loaderOpts.OnRead = func(uri string, data []byte) {
+		if schemaloader.IsRemote(uri) {
+			documents[uri] = data
+		}
+	}
+	_, err := schema.LoadAllSchemas(entries, &loaderOpts)
+	if err != nil {
+		return nil, err
+	}
+	return documents, nil
+}
+
+// Write writes documents to dir along with a lockfile. A document is written to a path made of its
+// URL's host and path. Files from a previous lockfile in dir that are no longer needed are removed.
+func Write(dir string, documents map[string][]byte) (*Lock, error) {
+	lockfile := filepath.Join(dir, LockfileName)
+	previous, err := ReadLock(lockfile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	lock := &Lock{URLMap: map[string]string{}}
+	for _, u := range slices.Sorted(maps.Keys(documents)) {
+		data := documents[u]
+		prefix, rel, err := localPath(u)
+		if err != nil {
+			return nil, err
+		}
+		filename := filepath.Join(dir, filepath.FromSlash(rel))
+		err = os.MkdirAll(filepath.Dir(filename), 0o700)
+		if err != nil {
+			return nil, fmt.Errorf("creating vendor directory: %w", err)
+		}
+		err = os.WriteFile(filename, data, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("writing vendored schema: %w", err)
+		}
+		sum := sha256.Sum256(data)
+		lock.Schemas = append(lock.Schemas, LockedSchema{
+			URL:    u,
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+		lock.URLMap[prefix] = strings.SplitN(rel, "/", 2)[0]
+	}
+
+	if previous != nil {
+		for _, locked := range previous.Schemas {
+			if _, ok := documents[locked.URL]; ok {
+				continue
+			}
+			_, rel, err := localPath(locked.URL)
+			if err != nil {
+				continue
+			}
+			err = os.Remove(filepath.Join(dir, filepath.FromSlash(rel)))
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return nil, fmt.Errorf("removing stale vendored schema: %w", err)
+			}
+		}
+	}
+
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("creating vendor directory: %w", err)
+	}
+	err = os.WriteFile(lockfile, append([]byte(lockfileHeader), data...), 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("writing lockfile: %w", err)
+	}
+	return lock, nil
+}
+
+// localPath returns the URL prefix that maps to a host directory and the slash separated path of
+// a document relative to the vendor directory.
+func localPath(u string) (prefix, rel string, _ error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing URL %q: %w", u, err)
+	}
+	if parsed.RawQuery != "" {
+		return "", "", fmt.Errorf("can't vendor %s: URLs with query strings aren't supported", u)
+	}
+	host := strings.ReplaceAll(parsed.Host, ":", "_")
+	// Cleaning a rooted path removes ".." elements so the file can't escape the host directory.
+	rel = host + path.Clean("/"+parsed.Path)
+	if host == "" || strings.HasSuffix(rel, "/") {
+		return "", "", fmt.Errorf("can't vendor %s: no file name in URL", u)
+	}
+	return parsed.Scheme + "://" + parsed.Host + "/", rel, nil
+}
@@ -0,0 +1,78 @@
+package schemavendor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	documents := map[string][]byte{
+		"https://example.com/schemas/person.json":    []byte(`{"type": "object"}`),
+		"https://example.com:8443/common/money.json": []byte(`{"type": "number"}`),
+	}
+	lock, err := Write(dir, documents)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"https://example.com/":      "example.com",
+		"https://example.com:8443/": "example.com_8443",
+	}, lock.URLMap)
+	assert.Equal(t, []LockedSchema{
+		{
+			URL:    "https://example.com/schemas/person.json",
+			SHA256: "ff419ebbeba438f66900abe77818ce940702bdfe70fa173cb94beecee8d3f112",
+		},
+		{
+			URL:    "https://example.com:8443/common/money.json",
+			SHA256: "dde3e535b2eebccebc99fdf4fa779094b7c03f0a1cabc89193f5bbe1ad1737d5",
+		},
+	}, lock.Schemas)
+
+	content, err := os.ReadFile(filepath.Join(dir, "example.com", "schemas", "person.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"type": "object"}`, string(content))
+
+	read, err := ReadLock(filepath.Join(dir, LockfileName))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "example.com"), read.URLMap["https://example.com/"])
+	assert.Equal(t, lock.Schemas, read.Schemas)
+
+	t.Run("removes stale files", func(t *testing.T) {
+		_, err := Write(dir, map[string][]byte{
+			"https://example.com/schemas/person.json": []byte(`{"type": "object"}`),
+		})
+		require.NoError(t, err)
+		_, err = os.Stat(filepath.Join(dir, "example.com_8443", "common", "money.json"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestLocalPath(t *testing.T) {
+	for _, test := range []struct {
+		url    string
+		prefix string
+		rel    string
+		err    string
+	}{
+		{url: "https://example.com/a/b.json", prefix: "https://example.com/", rel: "example.com/a/b.json"},
+		{url: "http://localhost:8080/b.yaml", prefix: "http://localhost:8080/", rel: "localhost_8080/b.yaml"},
+		{url: "https://example.com/../../etc/passwd", prefix: "https://example.com/", rel: "example.com/etc/passwd"},
+		{url: "https://example.com/", err: "can't vendor https://example.com/: no file name in URL"},
+		{url: "https://example.com/a.json?v=1", err: "can't vendor https://example.com/a.json?v=1: URLs with query strings aren't supported"},
+	} {
+		t.Run(test.url, func(t *testing.T) {
+			prefix, rel, err := localPath(test.url)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.prefix, prefix)
+			assert.Equal(t, test.rel, rel)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package watch reruns a function when the files it depends on change. It polls file metadata, so
+// it works anywhere without platform specific notification APIs.
+package watch
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Options for Run.
+type Options struct {
+	// Interval is how often files are polled for changes. Default: 250ms
+	Interval time.Duration
+	// Debounce is how long files must stay unchanged after a change before fn runs again.
+	// Default: 100ms
+	Debounce time.Duration
+}
+
+// Run calls fn and then calls it again every time one of the files returned by its previous call
+// changes. It returns when ctx is done.
+func Run(ctx context.Context, opts *Options, fn func() []string) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	interval := opts.Interval
+	if interval == 0 {
+		interval = 250 * time.Millisecond
+	}
+	debounce := opts.Debounce
+	if debounce == 0 {
+		debounce = 100 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		files := fn()
+		state := snapshot(files)
+
+		// Wait for a change
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+			}
+			if !state.equal(snapshot(files)) {
+				break
+			}
+		}
+
+		// Wait for the files to settle
+		settled := snapshot(files)
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(debounce):
+			}
+			current := snapshot(files)
+			if settled.equal(current) {
+				break
+			}
+			settled = current
+		}
+	}
+}
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+type filesState map[string]fileState
+
+func snapshot(files []string) filesState {
+	state := make(filesState, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			// Missing and unreadable files are both recorded as missing so that a change is noticed
+			// once they can be read.
+			state[file] = fileState{}
+			continue
+		}
+		state[file] = fileState{
+			exists:  true,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+	}
+	return state
+}
+
+func (s filesState) equal(other filesState) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for file, state := range s {
+		otherState, ok := other[file]
+		if !ok || state.exists != otherState.exists || state.size != otherState.size ||
+			!state.modTime.Equal(otherState.modTime) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package watch_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo/internal/watch"
+)
+
+func TestRun(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "schema.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("type: object\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls atomic.Int32
+	done := make(chan error)
+	go func() {
+		done <- watch.Run(ctx, &watch.Options{
+			Interval: 5 * time.Millisecond,
+			Debounce: 5 * time.Millisecond,
+		}, func() []string {
+			calls.Add(1)
+			return []string{file}
+		})
+	}()
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, os.WriteFile(file, []byte("type: string\n"), 0o600))
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+	require.NoError(t, os.Remove(file))
+	require.Eventually(t, func() bool { return calls.Load() == 3 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,407 @@
+// Package jsonschematogo generates Go types from JSON Schema documents.
+//
+// Generate is the simplest entry point. Load and Render split generation into its two steps for
+// callers that want to inspect the loaded schemas or reuse them.
+//
+// This package follows semantic versioning. Packages under internal/ are implementation details
+// and may change in any release.
+package jsonschematogo
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/willabides/jsonschematogo/internal/codegen"
+	"github.com/willabides/jsonschematogo/internal/inputs"
+	"github.com/willabides/jsonschematogo/internal/schema"
+	"github.com/willabides/jsonschematogo/internal/schemaloader"
+)
+
+// DefaultPackageName is the package name used when Config.PackageName is empty.
+const DefaultPackageName = "gen"
+
+// Config configures loading and rendering.
+type Config struct {
+	// Files are the entry schemas. Each is a local file path, a directory, a glob pattern or a URL.
+	// Directories are searched recursively for .json, .yaml and .yml files. Glob patterns support
+	// "**" to match any number of directories.
+	Files []string
+
+	// Include limits the files found in directories and glob patterns to those matching at least one
+	// of these patterns.
+	Include []string
+
+	// Exclude skips files found in directories and glob patterns that match any of these patterns.
+	Exclude []string
+
+	// Sources are schema documents keyed by URL or local path. They are used instead of reading the
+	// file or fetching the URL, so Files may name documents that only exist in memory.
+	Sources map[string][]byte
+
+	// FS mounts file systems such as embed.FS or fstest.MapFS at URL or local path prefixes. Documents
+	// under a prefix are read from the file system at the path that follows the prefix instead of
+	// from disk or the network. For example, with FS{"embed:///": schemas}, "embed:///person.json"
+	// is read from "person.json" in schemas, and its relative references resolve inside schemas too.
+	FS map[string]fs.FS
+
+	// URLMap maps URL prefixes to local directories. Schemas under a mapped prefix are read from
+	// the directory instead of being fetched.
+	URLMap map[string]string
+
+	// CACert is a PEM encoded CA certificate file for HTTPS connections.
+	CACert string
+
+	// Insecure skips TLS verification for HTTPS connections.
+	Insecure bool
+
+	// Headers are added to HTTP requests. They are keyed by host, with or without a port.
+	Headers map[string]http.Header
+
+	// BearerTokens are sent in the Authorization header of HTTP requests. They are keyed by host,
+	// with or without a port.
+	BearerTokens map[string]string
+
+	// Netrc is a netrc file with credentials for HTTP basic authentication. Bearer tokens and
+	// Authorization headers take precedence.
+	Netrc string
+
+	// Timeout for each HTTP request. Default: 15s
+	Timeout time.Duration
+
+	// Retries is how many times an HTTP request is retried after a network error or a 429 or 5xx
+	// response.
+	Retries int
+
+	// CacheDir is a directory for caching remote documents. Cached documents are revalidated with
+	// the server using their ETag or Last-Modified headers. No caching when empty.
+	CacheDir string
+
+	// Offline loads remote documents from CacheDir without network access. Loading fails when a
+	// document isn't cached.
+	Offline bool
+
+	// Refresh downloads remote documents again instead of revalidating cached copies.
+	Refresh bool
+
+	// Checksums are hex encoded SHA-256 checksums of documents keyed by URL. When set, loading fails
+	// when a document with a checksum has different content or when a remote document has no
+	// checksum.
+	Checksums map[string]string
+
+	// PackageName is the name of the generated package. Default: DefaultPackageName
+	PackageName string
+
+	// ImportPath is the import path of the generated package. Required when Packages is set.
+	ImportPath string
+
+	// Packages puts the types generated from some schemas into other Go packages.
+	Packages []Package
+
+	// SplitFiles generates one file per schema document instead of one file per package.
+	SplitFiles bool
+
+	// ExternalTypes are existing Go types to use instead of generating types for some schemas.
+	ExternalTypes []ExternalType
+
+	// PropertyOrder is the order of struct fields. Properties with an x-order extension always come
+	// first, sorted by it. Default: PropertyOrderAlphabetical
+	PropertyOrder PropertyOrder
+
+	// Optional is how fields for properties that aren't required are represented. The
+	// x-go-optional extension overrides it for a property. Default: OptionalPointer
+	Optional OptionalStrategy
+
+	// Getters generates a getter method for every field of a generated struct. Getters can be called
+	// on a nil receiver and return the property's default or the zero value when the property is
+	// absent. The x-go-getters extension overrides it for a struct.
+	Getters bool
+
+	// Builders generates a NewT constructor that takes the required fields of a generated struct T
+	// as parameters, a WithFoo setter for every other field and a generic Ptr helper.
+	Builders bool
+
+	// OnWarning is called by Render for every schema keyword that the generated types ignore, such
+	// as oneOf or patternProperties. Warnings are dropped when it is nil.
+	OnWarning func(*SchemaError)
+
+	// Strict makes Render fail with the warnings instead of passing them to OnWarning.
+	Strict bool
+
+	// Hooks customize the code Render generates, such as adding methods to generated types.
+	Hooks []Hook
+
+	// Templates are text/template templates for additional files keyed by file path relative to the
+	// output directory. Each template is executed with the IR as its data. Output for .go files is
+	// formatted with gofmt.
+	Templates map[string]string
+}
+
+// PropertyOrder is the order of the fields generated for an object's properties.
+type PropertyOrder string
+
+const (
+	// PropertyOrderAlphabetical sorts fields by property name.
+	PropertyOrderAlphabetical PropertyOrder = "alphabetical"
+	// PropertyOrderSource keeps fields in the order properties are declared in the schema document.
+	PropertyOrderSource PropertyOrder = "source"
+)
+
+// OptionalStrategy is how fields for properties that aren't required are represented.
+type OptionalStrategy string
+
+const (
+	// OptionalPointer uses a pointer to the property's type. Slices, maps and pointers are used as
+	// they are.
+	OptionalPointer OptionalStrategy = "pointer"
+	// OptionalOmitZero uses the property's type with the omitzero json option. It needs Go 1.24.
+	OptionalOmitZero OptionalStrategy = "omitzero"
+	// OptionalGeneric uses a generated Optional[T] that records whether the property is present.
+	// Omitting absent properties relies on omitzero and needs Go 1.24.
+	OptionalGeneric OptionalStrategy = "optional"
+	// OptionalNullable uses a generated Nullable[T] that records whether the property is present
+	// and whether it is null. Omitting absent properties needs Go 1.24.
+	OptionalNullable OptionalStrategy = "nullable"
+)
+
+// SchemaError is an error about a value in a schema document. It has the document location and
+// the line and column of the value. Errors from Load and Render wrap it when the problem is with a
+// specific value.
+type SchemaError = schema.Error
+
+// IR is the intermediate representation of generated code. It has the files to generate, the types
+// declared in each file with their fields and Go type expressions, and the location of the schema
+// each type and field comes from. It marshals to JSON.
+type IR = codegen.Model
+
+// Package is a Go package for the types generated from schemas under a URL prefix.
+type Package struct {
+	// URLPrefix is a URL or local path prefix. It is matched against schema locations, and the
+	// longest matching prefix wins.
+	URLPrefix string
+
+	// Dir is the package directory relative to the output directory and to Config.ImportPath.
+	Dir string
+}
+
+// ExternalType is an existing Go type that is used for references to schemas under a URL prefix.
+type ExternalType struct {
+	// URLPrefix is a URL or local path prefix. When TypeName is set, it must match the referenced
+	// schema's whole location, such as "https://example.com/money.json#/$defs/amount".
+	URLPrefix string
+
+	// ImportPath is the import path of the package that declares the type.
+	ImportPath string
+
+	// TypeName is the name of the type. When empty, the type name is derived from the reference
+	// the same way a generated type would be named.
+	TypeName string
+}
+
+// Generate loads the schemas in cfg.Files and renders Go code for them. The result maps file paths
+// relative to the output directory to their content. Without Packages or SplitFiles there is a
+// single file named after the package.
+func Generate(ctx context.Context, cfg Config) (map[string][]byte, error) {
+	schemas, err := Load(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return Render(schemas, cfg)
+}
+
+// Load loads the schemas in cfg.Files along with every document they reference. It returns one
+// Schema per entry file, in the order the files were found.
+func Load(ctx context.Context, cfg Config) ([]*Schema, error) {
+	entries, err := inputs.Expand(cfg.Files, &inputs.Options{
+		Include: cfg.Include,
+		Exclude: cfg.Exclude,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no schema files found")
+	}
+	sources := make(map[string][]byte, len(cfg.Sources))
+	for location, data := range cfg.Sources {
+		u, err := schemaloader.ToURL(location)
+		if err != nil {
+			return nil, err
+		}
+		sources[u] = data
+	}
+	mounts := make(map[string]fs.FS, len(cfg.FS))
+	for prefix, fsys := range cfg.FS {
+		urlPrefix, err := locationPrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		mounts[urlPrefix] = fsys
+	}
+	loaded, err := schema.LoadAllSchemas(entries, &schemaloader.Options{
+		Context:      ctx,
+		Mappings:     cfg.URLMap,
+		Sources:      sources,
+		FS:           mounts,
+		CACert:       cfg.CACert,
+		Insecure:     cfg.Insecure,
+		CacheDir:     cfg.CacheDir,
+		Offline:      cfg.Offline,
+		Refresh:      cfg.Refresh,
+		Checksums:    cfg.Checksums,
+		Headers:      cfg.Headers,
+		BearerTokens: cfg.BearerTokens,
+		Netrc:        cfg.Netrc,
+		Timeout:      cfg.Timeout,
+		Retries:      cfg.Retries,
+	})
+	if err != nil {
+		return nil, err
+	}
+	inMemory := func(document string) bool {
+		if _, ok := sources[document]; ok {
+			return true
+		}
+		for prefix := range mounts {
+			if strings.HasPrefix(document, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	schemas := make([]*Schema, 0, len(entries))
+	for _, entry := range entries {
+		schemas = append(schemas, newSchema(loaded[entry], inMemory))
+	}
+	return schemas, nil
+}
+
+// Render generates Go code for schemas returned by Load. Only the rendering options in cfg are used.
+// The result is the same as Generate's.
+func Render(schemas []*Schema, cfg Config) (map[string][]byte, error) {
+	ir, err := BuildIR(schemas, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var inMemory func(string) bool
+	if len(schemas) > 0 {
+		inMemory = schemas[0].inMemory
+	}
+	hooks := make([]codegen.Hook, 0, len(cfg.Hooks))
+	for _, hook := range cfg.Hooks {
+		hooks = append(hooks, &codegenHook{hook: hook, inMemory: inMemory})
+	}
+	files, err := codegen.RenderModel(ir, hooks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Templates)) {
+		if _, ok := files[name]; ok {
+			return nil, fmt.Errorf("template %s: a generated file has the same name", name)
+		}
+		files[name], err = codegen.RenderTemplate(ir, name, cfg.Templates[name])
+		if err != nil {
+			return nil, fmt.Errorf("template %s: %w", name, err)
+		}
+	}
+	return files, nil
+}
+
+// BuildIR returns the intermediate representation that Render turns into Go code. Only the
+// rendering options in cfg are used.
+func BuildIR(schemas []*Schema, cfg Config) (*IR, error) {
+	opts, err := cfg.codegenOptions()
+	if err != nil {
+		return nil, err
+	}
+	entrySchemas := make([]*schema.Schema, 0, len(schemas))
+	for _, sch := range schemas {
+		entrySchemas = append(entrySchemas, sch.schema)
+	}
+	ir, err := codegen.BuildModel(entrySchemas, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate code: %w", err)
+	}
+	return ir, nil
+}
+
+func (c *Config) codegenOptions() (*codegen.Options, error) {
+	if len(c.Packages) > 0 && c.ImportPath == "" {
+		return nil, fmt.Errorf("an import path is required with packages")
+	}
+	switch c.PropertyOrder {
+	case "", PropertyOrderAlphabetical, PropertyOrderSource:
+	default:
+		return nil, fmt.Errorf("unknown property order %q", c.PropertyOrder)
+	}
+	switch c.Optional {
+	case "", OptionalPointer, OptionalOmitZero, OptionalGeneric, OptionalNullable:
+	default:
+		return nil, fmt.Errorf("unknown optional strategy %q", c.Optional)
+	}
+	opts := &codegen.Options{
+		PackageName: c.PackageName,
+		PackagePath: c.ImportPath,
+		SplitFiles:  c.SplitFiles,
+		SourceOrder: c.PropertyOrder == PropertyOrderSource,
+		Optional:    codegen.OptionalStrategy(c.Optional),
+		Getters:     c.Getters,
+		Builders:    c.Builders,
+		OnWarning:   c.OnWarning,
+		Strict:      c.Strict,
+	}
+	if opts.PackageName == "" {
+		opts.PackageName = DefaultPackageName
+	}
+	for _, pkg := range c.Packages {
+		urlPrefix, err := locationPrefix(pkg.URLPrefix)
+		if err != nil {
+			return nil, err
+		}
+		dir := path.Clean(filepath.ToSlash(pkg.Dir))
+		opts.Packages = append(opts.Packages, codegen.Package{
+			URLPrefix: urlPrefix,
+			Path:      path.Join(c.ImportPath, dir),
+			Dir:       dir,
+		})
+	}
+	for _, ext := range c.ExternalTypes {
+		if ext.ImportPath == "" {
+			return nil, fmt.Errorf("external type for %q is missing an import path", ext.URLPrefix)
+		}
+		urlPrefix, err := locationPrefix(ext.URLPrefix)
+		if err != nil {
+			return nil, err
+		}
+		opts.ExternalTypes = append(opts.ExternalTypes, codegen.ExternalType{
+			URLPrefix: urlPrefix,
+			Path:      ext.ImportPath,
+			Name:      ext.TypeName,
+		})
+	}
+	return opts, nil
+}
+
+// locationPrefix converts a local path prefix to the file URL prefix it matches in schema
+// locations. Prefixes that are already URLs are returned unchanged. A trailing separator is kept so
+// that "schemas/" doesn't match "schemas-old".
+func locationPrefix(prefix string) (string, error) {
+	if strings.Contains(prefix, "://") {
+		return prefix, nil
+	}
+	urlPrefix, err := schemaloader.ToURL(prefix)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasSuffix(prefix, "/") || strings.HasSuffix(prefix, string(filepath.Separator)) {
+		urlPrefix += "/"
+	}
+	return urlPrefix, nil
+}
@@ -0,0 +1,216 @@
+package jsonschematogo_test
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/dave/jennifer/jen"
+	"github.com/stretchr/testify/require"
+	"github.com/willabides/jsonschematogo"
+)
+
+const personSchema = `{
+  "title": "Person",
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "address": {"$ref": "address.json"}
+  },
+  "required": ["name"]
+}`
+
+const addressSchema = `{
+  "title": "Address",
+  "type": "object",
+  "properties": {
+    "city": {"type": "string"}
+  }
+}`
+
+func memorySources() map[string][]byte {
+	return map[string][]byte{
+		"memory/person.json":  []byte(personSchema),
+		"memory/address.json": []byte(addressSchema),
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("single file", func(t *testing.T) {
+		files, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+			Files:       []string{"memory/person.json"},
+			Sources:     memorySources(),
+			PackageName: "people",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"people.go"}, slices.Sorted(maps.Keys(files)))
+		content := string(files["people.go"])
+		require.Contains(t, content, "package people")
+		require.Contains(t, content, "type Person struct")
+		require.Contains(t, content, "type Address struct")
+	})
+
+	t.Run("packages", func(t *testing.T) {
+		files, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+			Files:      []string{"memory/person.json"},
+			Sources:    memorySources(),
+			ImportPath: "example.com/people",
+			SplitFiles: true,
+			Packages: []jsonschematogo.Package{
+				{URLPrefix: "memory/address.json", Dir: "address"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"address/address.go", "person.go"}, slices.Sorted(maps.Keys(files)))
+		require.Contains(t, string(files["person.go"]), `"example.com/people/address"`)
+	})
+
+	t.Run("external types", func(t *testing.T) {
+		files, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+			Files:   []string{"memory/person.json"},
+			Sources: memorySources(),
+			ExternalTypes: []jsonschematogo.ExternalType{
+				{URLPrefix: "memory/address.json", ImportPath: "example.com/geo", TypeName: "Location"},
+			},
+		})
+		require.NoError(t, err)
+		content := string(files["gen.go"])
+		require.Contains(t, content, "geo.Location")
+		require.NotContains(t, content, "type Address struct")
+	})
+
+	t.Run("fs", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"person.json":  {Data: []byte(personSchema)},
+			"address.json": {Data: []byte(addressSchema)},
+		}
+		for _, prefix := range []string{"embed:///", "schemas/"} {
+			schemas, err := jsonschematogo.Load(context.Background(), jsonschematogo.Config{
+				Files: []string{prefix + "person.json"},
+				FS:    map[string]fs.FS{prefix: fsys},
+			})
+			require.NoError(t, err, prefix)
+			require.Len(t, schemas[0].Documents(), 2, prefix)
+			require.Empty(t, schemas[0].LocalFiles(), prefix)
+			files, err := jsonschematogo.Render(schemas, jsonschematogo.Config{})
+			require.NoError(t, err, prefix)
+			require.Contains(t, string(files["gen.go"]), "type Address struct", prefix)
+		}
+	})
+
+	t.Run("packages without import path", func(t *testing.T) {
+		_, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+			Files:    []string{"memory/person.json"},
+			Sources:  memorySources(),
+			Packages: []jsonschematogo.Package{{URLPrefix: "memory/", Dir: "memory"}},
+		})
+		require.EqualError(t, err, "an import path is required with packages")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+			Files: []string{"testdata/does-not-exist.json"},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestLoad(t *testing.T) {
+	schemas, err := jsonschematogo.Load(context.Background(), jsonschematogo.Config{
+		Files:   []string{"memory/person.json"},
+		Sources: memorySources(),
+	})
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+	person := schemas[0]
+	require.Equal(t, "object", person.Type())
+	require.Equal(t, []string{"name"}, person.Required())
+	require.Equal(t, []string{"address", "name"}, slices.Sorted(maps.Keys(person.Properties())))
+	require.Equal(t, "address.json", person.Properties()["address"].Ref())
+	require.Len(t, person.Documents(), 2)
+	require.Empty(t, person.LocalFiles())
+}
+
+func ExampleGenerate() {
+	files, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+		Files: []string{"person.json"},
+		Sources: map[string][]byte{
+			"person.json": []byte(`{
+  "title": "Person",
+  "type": "object",
+  "properties": {"name": {"type": "string"}},
+  "required": ["name"]
+}`),
+		},
+		PackageName: "people",
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(string(files["people.go"]))
+	// Output:
+	// // Code generated by jsonschematogo. DO NOT EDIT.
+	//
+	// package people
+	//
+	// type Person struct {
+	// 	Name string `json:"name"`
+	// }
+}
+
+// tableNameHook adds a TableName method to types with an x-table-name extension and a db tag to
+// their fields.
+type tableNameHook struct{}
+
+func (tableNameHook) Type(ctx *jsonschematogo.TypeHookContext) error {
+	tableName, ok := ctx.Extensions["x-table-name"].(string)
+	if !ok {
+		return nil
+	}
+	ctx.After = append(ctx.After, jen.Func().Params(jen.Id(ctx.Name)).Id("TableName").Params().String().Block(
+		jen.Return(jen.Lit(tableName)),
+	))
+	return nil
+}
+
+func (tableNameHook) Field(ctx *jsonschematogo.FieldHookContext) error {
+	ctx.Tags["db"] = ctx.JSONName
+	return nil
+}
+
+func ExampleHook() {
+	files, err := jsonschematogo.Generate(context.Background(), jsonschematogo.Config{
+		Files: []string{"person.json"},
+		Sources: map[string][]byte{
+			"person.json": []byte(`{
+  "title": "Person",
+  "x-table-name": "people",
+  "type": "object",
+  "properties": {"name": {"type": "string", "x-go-tags": {"xml": "name"}}},
+  "required": ["name"]
+}`),
+		},
+		PackageName: "people",
+		Hooks:       []jsonschematogo.Hook{tableNameHook{}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Print(string(files["people.go"]))
+	// Output:
+	// // Code generated by jsonschematogo. DO NOT EDIT.
+	//
+	// package people
+	//
+	// type Person struct {
+	// 	Name string `db:"name" json:"name" xml:"name"`
+	// }
+	//
+	// func (Person) TableName() string {
+	// 	return "people"
+	// }
+}
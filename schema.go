@@ -0,0 +1,118 @@
+package jsonschematogo
+
+import (
+	"net/url"
+	"path/filepath"
+
+	"github.com/willabides/jsonschematogo/internal/schema"
+)
+
+// Schema is a loaded JSON Schema. The methods give read only access to the parts of the schema that
+// code generation uses.
+type Schema struct {
+	schema *schema.Schema
+	// inMemory reports whether a document URL was loaded from Config.Sources or Config.FS.
+	inMemory func(document string) bool
+}
+
+func newSchema(sch *schema.Schema, inMemory func(string) bool) *Schema {
+	if sch == nil {
+		return nil
+	}
+	return &Schema{
+		schema:   sch,
+		inMemory: inMemory,
+	}
+}
+
+// Location returns the absolute URL of the schema, such as "file:///schemas/order.json#/$defs/item".
+func (s *Schema) Location() string {
+	return s.schema.Location()
+}
+
+// Type returns the schema's type keyword, or the first type when there are several. It is empty when
+// the schema has no type.
+func (s *Schema) Type() string {
+	return s.schema.Type()
+}
+
+// Ref returns the value of the schema's $ref keyword.
+func (s *Schema) Ref() string {
+	return s.schema.Ref()
+}
+
+// RefSchema returns the schema that $ref points to, or nil when there is no $ref.
+func (s *Schema) RefSchema() *Schema {
+	return newSchema(s.schema.RefSchema(), s.inMemory)
+}
+
+// Properties returns the schemas of the object's properties keyed by property name.
+func (s *Schema) Properties() map[string]*Schema {
+	props := s.schema.Properties()
+	if props == nil {
+		return nil
+	}
+	result := make(map[string]*Schema, len(props))
+	for name, prop := range props {
+		result[name] = newSchema(prop, s.inMemory)
+	}
+	return result
+}
+
+// Required returns the names of the required properties.
+func (s *Schema) Required() []string {
+	return s.schema.Required()
+}
+
+// Items returns the schema for array items, or nil when there is none.
+func (s *Schema) Items() *Schema {
+	return newSchema(s.schema.Items(), s.inMemory)
+}
+
+// Definition is a schema declared in $defs or definitions.
+type Definition struct {
+	// Name is the key in $defs or definitions.
+	Name string
+	// Ref is the fragment that refers to the definition, such as "#/$defs/item".
+	Ref string
+	// Schema is the definition's schema.
+	Schema *Schema
+}
+
+// Definitions returns the schema's definitions sorted by name. Only entry schemas returned by Load
+// have definitions.
+func (s *Schema) Definitions() []Definition {
+	var definitions []Definition
+	for definition := range s.schema.OrderedDefinitions() {
+		definitions = append(definitions, Definition{
+			Name:   definition.Name,
+			Ref:    definition.Ref,
+			Schema: newSchema(definition.Schema, s.inMemory),
+		})
+	}
+	return definitions
+}
+
+// Documents returns the sorted URLs of every document that was loaded for an entry schema. It is
+// empty for schemas that aren't entry schemas.
+func (s *Schema) Documents() []string {
+	return s.schema.Documents()
+}
+
+// LocalFiles returns the local file paths of the documents in Documents. Remote documents and
+// documents from Config.Sources or Config.FS are skipped. Tools use it to know which files to
+// watch.
+func (s *Schema) LocalFiles() []string {
+	var paths []string
+	for _, document := range s.schema.Documents() {
+		if s.inMemory(document) {
+			continue
+		}
+		u, err := url.Parse(document)
+		if err != nil || u.Scheme != "file" {
+			continue
+		}
+		paths = append(paths, filepath.FromSlash(u.Path))
+	}
+	return paths
+}